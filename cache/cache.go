@@ -0,0 +1,82 @@
+// Package cache keeps data fetched from remote APIs between runs.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Dir returns the cache directory, following the XDG base directory specification.
+func Dir() (string, error) {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(dir, "lazyai"), nil
+}
+
+// Load decodes the cache entry name into v. It reports whether the entry exists and returns when it was saved.
+func Load(name string, v any) (time.Time, bool, error) {
+	dir, err := Dir()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	path := filepath.Join(dir, name)
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("error reading cache: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		// A corrupt entry is as good as a missing one
+		return time.Time{}, false, nil
+	}
+	return info.ModTime(), true, nil
+}
+
+// Save stores v as the cache entry name. Names may contain slashes to group entries in subdirectories.
+func Save(name string, v any) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+	// Write to a temporary file first so that concurrent runs never read a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error writing cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing cache: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
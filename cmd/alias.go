@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/nlgtEA/lazyai/skydeck"
+	"github.com/spf13/cobra"
+)
+
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]*$`)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage names for SkyDeck conversations",
+	Long: `Aliases give conversations memorable names that can be used with "sdchat -c" instead of their numeric IDs.
+Each profile has its own aliases.
+
+Examples:
+    # Name the conversation bound to the current scope
+    lazyai sdchat alias set auth-refactor
+
+    # Name a specific conversation
+    lazyai sdchat alias set auth-refactor 123
+
+    # Continue the conversation by its name
+    lazyai sdchat -c auth-refactor "What's left to do?"
+`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> [conversation]",
+	Short: "Name a conversation, by default the one bound to the current scope",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !aliasNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid alias %q, aliases start with a letter and contain only letters, digits, '.', '_' and '-'", name)
+		}
+
+		var id int
+		var err error
+		if len(args) == 2 {
+			if id, err = resolveConversation(args[1]); err != nil {
+				return err
+			}
+		} else if id = boundConversation(scopeKey(scope)); id == 0 {
+			return errors.New("no conversation is bound to the current scope, pass the conversation ID")
+		}
+
+		appState.Aliases[profileKey(name)] = id
+		if err := appState.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("%s -> %s\n", name, skydeck.ConversationURL(id))
+		return nil
+	},
+	ValidArgsFunction: cobra.NoFileCompletions,
+}
+
+var aliasRmCmd = &cobra.Command{
+	Use:     "rm <name>...",
+	Aliases: []string{"remove"},
+	Short:   "Remove conversation aliases",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, name := range args {
+			if _, ok := appState.Aliases[profileKey(name)]; !ok {
+				return fmt.Errorf("unknown alias %q", name)
+			}
+			delete(appState.Aliases, profileKey(name))
+		}
+		return appState.Save()
+	},
+	ValidArgsFunction: completeAliases,
+}
+
+var aliasLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List conversation aliases",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		aliases := profileEntries(appState.Aliases)
+		if len(aliases) == 0 {
+			fmt.Println("No conversation aliases yet.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ALIAS\tCONVERSATION\tURL")
+		for _, name := range aliasNames() {
+			id := aliases[name]
+			fmt.Fprintf(w, "%s\t%d\t%s\n", name, id, skydeck.ConversationURL(id))
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd, aliasRmCmd, aliasLsCmd)
+	sdchatCmd.AddCommand(aliasCmd)
+}
+
+// resolveConversation turns a conversation ID or alias into a conversation ID. An empty reference resolves to 0.
+func resolveConversation(ref string) (int, error) {
+	if ref == "" {
+		return 0, nil
+	}
+	if id, err := strconv.Atoi(ref); err == nil {
+		return id, nil
+	}
+	if id, ok := appState.Aliases[profileKey(ref)]; ok {
+		return id, nil
+	}
+	return 0, fmt.Errorf("unknown conversation alias %q, see \"lazyai sdchat alias ls\"", ref)
+}
+
+// aliasNames returns the sorted aliases of the active profile.
+func aliasNames() []string {
+	aliases := profileEntries(appState.Aliases)
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func completeAliases(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if appState == nil && loadState(cmd, args) != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := aliasNames()
+	completions := make([]string, 0, len(names))
+	for _, name := range names {
+		completions = append(completions, fmt.Sprintf("%s\tconversation %d", name, appState.Aliases[profileKey(name)]))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
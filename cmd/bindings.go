@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/nlgtEA/lazyai/skydeck"
+	"github.com/spf13/cobra"
+)
+
+var resetAllBindings bool
+
+var bindingsCmd = &cobra.Command{
+	Use:   "bindings",
+	Short: "List the conversations bound to each scope",
+	Long: `The bindings command lists which SkyDeck conversation each scope continues.
+The binding of the current scope is marked with an asterisk. Each profile has its own bindings.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		current := scopeKey(scope)
+		bindings := profileEntries(appState.Bindings)
+
+		keys := make([]string, 0, len(bindings))
+		for key := range bindings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		if len(keys) == 0 {
+			fmt.Println("No conversation bindings yet.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "\tSCOPE\tCONVERSATION\tURL")
+		for _, key := range keys {
+			marker := ""
+			if key == current {
+				marker = "*"
+			}
+			id := bindings[key]
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", marker, key, id, skydeck.ConversationURL(id))
+		}
+		w.Flush()
+	},
+}
+
+var resetBindingsCmd = &cobra.Command{
+	Use:   "reset [scope key...]",
+	Short: "Forget conversation bindings so that the next message starts a new conversation",
+	Long: `The reset command forgets the binding of the current scope, or of the given scope keys as shown by "sdchat bindings".
+
+Examples:
+    # Start over on the current branch
+    lazyai sdchat bindings reset
+
+    # Start over in the repository wide conversation
+    lazyai sdchat bindings reset --scope repo
+
+    # Forget all bindings
+    lazyai sdchat bindings reset --all
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bindings := profileEntries(appState.Bindings)
+		keys := args
+		switch {
+		case resetAllBindings:
+			keys = nil
+			for key := range bindings {
+				keys = append(keys, key)
+			}
+		case len(keys) == 0:
+			keys = []string{scopeKey(scope)}
+		}
+
+		for _, key := range keys {
+			if _, ok := bindings[key]; !ok && !resetAllBindings {
+				fmt.Fprintf(os.Stderr, "No conversation is bound to %s\n", key)
+				continue
+			}
+			delete(appState.Bindings, profileKey(key))
+			fmt.Printf("Forgot the conversation bound to %s\n", key)
+		}
+
+		return appState.Save()
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if appState == nil && loadState(cmd, args) != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		bindings := profileEntries(appState.Bindings)
+		keys := make([]string, 0, len(bindings))
+		for key := range bindings {
+			keys = append(keys, key)
+		}
+		return keys, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+func init() {
+	resetBindingsCmd.Flags().BoolVarP(&resetAllBindings, "all", "a", false, "Forget the bindings of all scopes")
+
+	bindingsCmd.AddCommand(resetBindingsCmd)
+	sdchatCmd.AddCommand(bindingsCmd)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/prompt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commitAll    bool
+	commitAmend  bool
+	commitDryRun bool
+)
+
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Generate a commit message for your staged changes and commit them",
+	Long: `The commit command sends your staged changes to SkyDeck in a fresh conversation and asks for a commit message.
+The draft is opened in your $EDITOR, after which you can accept it, regenerate it or abort.
+
+Examples:
+    # Commit the staged changes
+    lazyai commit
+
+    # Commit all tracked changes, like git commit -a
+    lazyai commit --all
+
+    # Rewrite the message of the last commit, including any staged changes
+    lazyai commit --amend
+
+    # Print the message without committing
+    lazyai commit --dry-run
+`,
+	Args:    cobra.NoArgs,
+	PreRunE: requireSettings,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		diff, err := git.CollectDiff(commitDiffOptions())
+		if err != nil {
+			return err
+		}
+		if diff.IsEmpty() {
+			return errors.New("nothing to commit, stage your changes first or use --all")
+		}
+
+		apiClient := newSkydeckClient()
+		patch, summaries, err := condenseDiff(apiClient, diff, "")
+		if err != nil {
+			return err
+		}
+
+		data := prompt.CommitData{Diff: patch, Excluded: diff.ExcludedSummary(), Summaries: summaries}
+		data.StoryID, data.StoryURL = currentStory(defaultBaseRef("origin"))
+		message, err := prompt.Commit(data)
+		if err != nil {
+			return fmt.Errorf("error rendering commit prompt: %w", err)
+		}
+
+		commitMessage, err := reviewDraft(apiClient, message, "lazyai-commit-*.txt", stripComments)
+		if err != nil {
+			return err
+		}
+
+		if commitDryRun {
+			fmt.Println(commitMessage)
+			return nil
+		}
+
+		return gitCommit(commitMessage)
+	},
+}
+
+func init() {
+	commitCmd.Flags().BoolVarP(&commitAll, "all", "a", false, "Include all modified tracked files, like git commit -a")
+	commitCmd.Flags().BoolVar(&commitAmend, "amend", false, "Replace the last commit, describing its changes together with the staged ones")
+	commitCmd.Flags().BoolVar(&commitDryRun, "dry-run", false, "Print the commit message instead of committing")
+
+	rootCmd.AddCommand(commitCmd)
+}
+
+// commitDiffOptions selects the changes that the commit will contain.
+func commitDiffOptions() git.DiffOptions {
+	opts := git.StagedDiff()
+	if commitAll {
+		opts = git.WorkingTreeDiff()
+	}
+
+	if commitAmend {
+		opts.Base = "HEAD^"
+		if !git.HasCommit(opts.Base) {
+			opts.Base = git.EmptyTree
+		}
+	}
+	return opts
+}
+
+func gitCommit(message string) error {
+	args := []string{"commit", "--cleanup=strip", "-F", "-"}
+	if commitAll {
+		args = append(args, "--all")
+	}
+	if commitAmend {
+		args = append(args, "--amend")
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = strings.NewReader(message + "\n")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
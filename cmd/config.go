@@ -0,0 +1,459 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/nlgtEA/lazyai/config"
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	forceSet bool
+	useRepo  bool
+)
+
+var configCmd = &cobra.Command{
+	Use:               "config",
+	PersistentPreRunE: loadSettingsLenient,
+	Short:             "Create, inspect and validate the configuration file",
+	Long: `The config command manages the ~/.lazyai.yml configuration file.
+
+Examples:
+    # Create the configuration file interactively
+    lazyai config init
+
+    # Change a single setting, in the work profile
+    lazyai config set --profile work skydeck.modelID 4094
+
+    # Share the Pivotal Tracker project with your team through the repository's .lazyai.yml
+    lazyai config set --repo pivotalTracker.projectID 123456
+
+    # Check the configuration for typos and missing values
+    lazyai config validate
+`,
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create or update the configuration file interactively",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		doc, err := config.LoadDocument(configFile())
+		if err != nil {
+			return err
+		}
+
+		// Write to the section of the active profile, and start from what that section holds rather than
+		// from the merged settings, which include other sections, the environment and the repository
+		section := func(key string) string {
+			return profileSettingKey(activeProfile(), key)
+		}
+
+		values := map[string]*string{}
+		input := func(key, title string) *huh.Input {
+			value := documentValue(doc, section(key))
+			values[key] = &value
+
+			field := huh.NewInput().Title(title).Value(&value)
+			if setting, _ := config.Lookup(key); setting.Secret {
+				field.EchoMode(huh.EchoModePassword)
+			}
+			if setting, _ := config.Lookup(key); setting.Kind == config.Int {
+				field.Validate(func(s string) error {
+					if s == "" {
+						return nil
+					}
+					_, err := setting.Parse(s)
+					return err
+				})
+			}
+			return field
+		}
+
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewNote().Title("SkyDeck").Description("Copy the eastagile_access and eastagile_refresh cookies from your browser."),
+				input("skydeck.accessToken", "Access token"),
+				input("skydeck.refreshToken", "Refresh token"),
+			),
+			huh.NewGroup(
+				huh.NewNote().Title("Pivotal Tracker").Description("Your API token is at the bottom of your Tracker profile page. Leave empty to skip."),
+				input("pivotalTracker.apiToken", "API token"),
+			),
+			huh.NewGroup(
+				huh.NewNote().Title("GitHub").Description("Used by \"lazyai pr\". Leave empty to use the gh CLI instead."),
+				input("github.token", "Personal access token"),
+			),
+		)
+		if err := form.Run(); err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value := strings.TrimSpace(*values[key])
+			if value == "" {
+				continue
+			}
+
+			setting, _ := config.Lookup(key)
+			parsed, err := setting.Parse(value)
+			if err != nil {
+				return err
+			}
+			if err := doc.Set(section(key), parsed); err != nil {
+				return err
+			}
+		}
+
+		if token := strings.TrimSpace(*values["pivotalTracker.apiToken"]); token != "" {
+			if err := pickProjects(cmd, doc, token, section); err != nil {
+				return err
+			}
+		}
+
+		if err := doc.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Configuration written to %s\n", doc.Path())
+		return nil
+	},
+}
+
+// pickProjects asks which of the Tracker projects of the user stories are picked from, and records them
+// in doc under the keys returned by section.
+func pickProjects(cmd *cobra.Command, doc *config.Document, token string, section func(string) string) error {
+	me, err := pivotal.NewClient(token).Me(cmd.Context())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot list your Pivotal Tracker projects: %v\n", err)
+		return nil
+	}
+	fmt.Printf("Signed in to Pivotal Tracker as %s (%s)\n", me.Name, me.Username)
+	if len(me.Projects) < 2 {
+		// Stories are listed from all the projects of the user by default
+		return nil
+	}
+
+	current := map[string]bool{}
+	for _, id := range append(documentList(doc, section("pivotalTracker.projectIDs")), documentList(doc, section("pivotalTracker.projectID"))...) {
+		current[id] = true
+	}
+	options := make([]huh.Option[int], len(me.Projects))
+	for i, project := range me.Projects {
+		options[i] = huh.NewOption(project.ProjectName, project.ProjectID).Selected(current[strconv.Itoa(project.ProjectID)])
+	}
+
+	var picked []int
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewMultiSelect[int]().
+			Title("Projects to pick stories from").
+			Description("Pick none to use all of them.").
+			Options(options...).
+			Value(&picked),
+	))
+	if err := form.Run(); err != nil {
+		return err
+	}
+
+	doc.Unset(section("pivotalTracker.projectID"))
+	doc.Unset(section("pivotalTracker.projectIDs"))
+	switch len(picked) {
+	case 0:
+		return nil
+	case 1:
+		return doc.Set(section("pivotalTracker.projectID"), picked[0])
+	default:
+		return doc.Set(section("pivotalTracker.projectIDs"), picked)
+	}
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !viper.IsSet(args[0]) {
+			return fmt.Errorf("%s is not set", args[0])
+		}
+
+		value := viper.Get(args[0])
+		if m, ok := value.(map[string]any); ok {
+			keys := make([]string, 0, len(m))
+			for key := range m {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("%s: %v\n", key, m[key])
+			}
+			return nil
+		}
+
+		fmt.Println(value)
+		return nil
+	},
+	ValidArgsFunction: completeConfigKeys,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the configuration file, or in the active profile",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+
+		var parsed any = value
+		if setting, ok := config.Lookup(key); ok {
+			var err error
+			if parsed, err = setting.Parse(value); err != nil {
+				return err
+			}
+		} else if !forceSet {
+			return fmt.Errorf("unknown key %s, see \"lazyai config set --help\" or use --force", key)
+		}
+
+		path, err := targetConfigFile(key)
+		if err != nil {
+			return err
+		}
+		target, err := settingKey(key)
+		if err != nil {
+			return err
+		}
+		return config.Set(path, target, parsed)
+	},
+	ValidArgsFunction: completeConfigKeys,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting from the configuration file, or from the active profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := targetConfigFile(args[0])
+		if err != nil {
+			return err
+		}
+		key, err := settingKey(args[0])
+		if err != nil {
+			return err
+		}
+		doc, err := config.LoadDocument(path)
+		if err != nil {
+			return err
+		}
+
+		if !doc.Unset(key) {
+			return fmt.Errorf("%s is not set in %s", key, doc.Path())
+		}
+		return doc.Save()
+	},
+	ValidArgsFunction: completeConfigKeys,
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the location of the configuration file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := targetConfigFile("")
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Report unknown keys, values of the wrong type and settings missing for each command",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		doc, err := config.LoadDocument(configFile())
+		if err != nil {
+			return err
+		}
+		settings, err := doc.Settings()
+		if err != nil {
+			return err
+		}
+
+		problems := config.Validate(settings)
+
+		if repoConfigFile != "" {
+			repoDoc, err := config.LoadDocument(repoConfigFile)
+			if err != nil {
+				return err
+			}
+			repoSettings, err := repoDoc.Settings()
+			if err != nil {
+				return err
+			}
+			for _, problem := range config.Validate(repoSettings) {
+				problems = append(problems, fmt.Sprintf("%s: %s", repoConfigFile, problem))
+			}
+		}
+
+		commands := make([]string, 0, len(config.Required))
+		for command := range config.Required {
+			commands = append(commands, command)
+		}
+		sort.Strings(commands)
+		for _, command := range commands {
+			for _, key := range config.Missing(command, settingValue) {
+				problems = append(problems, fmt.Sprintf("%s is required by \"lazyai %s\" but not set", key, command))
+			}
+		}
+
+		if len(problems) == 0 {
+			fmt.Printf("%s is valid\n", doc.Path())
+			return nil
+		}
+
+		fmt.Fprintf(os.Stderr, "%s has %d problem(s):\n", doc.Path(), len(problems))
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		return errors.New("invalid configuration")
+	},
+}
+
+func init() {
+	configSetCmd.Flags().BoolVarP(&forceSet, "force", "f", false, "Set keys lazyai does not know about")
+	for _, c := range []*cobra.Command{configSetCmd, configUnsetCmd, configPathCmd} {
+		c.Flags().BoolVar(&useRepo, "repo", false, "Use the .lazyai.yml file shared at the root of the current git repository")
+	}
+
+	var keys []string
+	for _, key := range config.Keys {
+		keys = append(keys, fmt.Sprintf("    %-26s %-8s %s", key.Name, key.Kind, key.Description))
+	}
+	configSetCmd.Long = "Known keys:\n" + strings.Join(keys, "\n")
+	configGetCmd.Long = configSetCmd.Long
+
+	configCmd.AddCommand(configInitCmd, configGetCmd, configSetCmd, configUnsetCmd, configPathCmd, configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// settingKey returns the key a setting is written to, inside the active profile if any. Profiles
+// cannot be shared, so there is no key in the repository's configuration file while one is active.
+func settingKey(key string) (string, error) {
+	profile := activeProfile()
+	if useRepo && profile != "" {
+		return "", fmt.Errorf("profile %s is active, profiles cannot be shared through the repository's configuration file", profile)
+	}
+	return profileSettingKey(profile, key), nil
+}
+
+// profileSettingKey returns the key of a setting in the section of a profile, or the top-level key without profile.
+func profileSettingKey(profile, key string) string {
+	if profile != "" {
+		return config.ProfilesKey + "." + profile + "." + key
+	}
+	return key
+}
+
+// targetConfigFile returns the file config subcommands operate on: the
+// user's configuration file, or the repository's one with --repo.
+func targetConfigFile(key string) (string, error) {
+	if !useRepo {
+		return configFile(), nil
+	}
+
+	if key != "" && config.IsUserOnly(key) {
+		return "", fmt.Errorf("%s cannot be shared through the repository's configuration file", key)
+	}
+	root, err := git.RepoRoot()
+	if err != nil {
+		return "", err
+	}
+	return config.RepoPath(root), nil
+}
+
+// requireSettings fails when settings needed by the command are missing from the configuration.
+func requireSettings(cmd *cobra.Command, args []string) error {
+	return requireCommandSettings(cmd.Name())
+}
+
+// requireCommandSettings fails when settings listed for command in config.Required are missing from the configuration.
+func requireCommandSettings(command string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+
+	missing := config.Missing(command, settingValue)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s must be set in the configuration file %s.\nRun \"lazyai config init\" or \"lazyai config set <key> <value>\" to set them", strings.Join(missing, ", "), configFile())
+}
+
+// settingValue returns the value of a setting as a string, joining lists with commas.
+func settingValue(key string) string {
+	return strings.Join(settingList(key), ",")
+}
+
+// settingList returns the values of a list setting, given as a YAML sequence or as comma-separated values.
+func settingList(key string) []string {
+	return listValue(viper.Get(key))
+}
+
+// documentValue returns a setting as written in a configuration file, without any override.
+func documentValue(doc *config.Document, key string) string {
+	value, ok := doc.Get(key)
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// documentList returns the values of a setting as written in a configuration file, without any override.
+func documentList(doc *config.Document, key string) []string {
+	value, _ := doc.Get(key)
+	return listValue(value)
+}
+
+func listValue(value any) []string {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			values = append(values, fmt.Sprint(v))
+		}
+		return values
+	case []string:
+		return value
+	default:
+		return config.SplitList(fmt.Sprint(value))
+	}
+}
+
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, len(config.Keys))
+	for i, key := range config.Keys {
+		completions[i] = key.Name + "\t" + key.Description
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
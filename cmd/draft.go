@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/nlgtEA/lazyai/skydeck"
+)
+
+const (
+	actionAccept     = "accept"
+	actionEdit       = "edit"
+	actionRegenerate = "regenerate"
+	actionAbort      = "abort"
+)
+
+// reviewDraft asks the model for a draft and lets the user edit, regenerate or abort it.
+// The edited draft is passed through clean before being shown and returned.
+func reviewDraft(apiClient *skydeck.APIClient, message, pattern string, clean func(string) string) (string, error) {
+	warnIfOverBudget(message)
+
+	for {
+		fmt.Fprintln(os.Stderr, "Generating draft...")
+		draft, err := apiClient.Ask(message, modelID())
+		if err != nil {
+			return "", err
+		}
+		draft = stripFence(draft)
+
+		for {
+			edited, err := editText(draft+"\n", pattern)
+			if err != nil {
+				return "", err
+			}
+			draft = clean(edited)
+
+			fmt.Fprintf(os.Stderr, "\n%s\n\n", draft)
+
+			action := actionAccept
+			form := huh.NewForm(
+				huh.NewGroup(
+					huh.NewSelect[string]().
+						Title("What would you like to do?").
+						Options(
+							huh.NewOption("Accept", actionAccept),
+							huh.NewOption("Edit again", actionEdit),
+							huh.NewOption("Regenerate", actionRegenerate),
+							huh.NewOption("Abort", actionAbort),
+						).
+						Value(&action),
+				),
+			)
+			if err := form.Run(); err != nil {
+				return "", err
+			}
+
+			switch action {
+			case actionAccept:
+				if draft == "" {
+					return "", errors.New("aborting due to empty message")
+				}
+				return draft, nil
+			case actionAbort:
+				return "", errors.New("aborted")
+			}
+
+			if action == actionRegenerate {
+				break
+			}
+		}
+	}
+}
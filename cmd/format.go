@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/nlgtEA/lazyai/tracker"
+)
+
+// issueFormatter writes issues in one of the formats accepted by --format.
+type issueFormatter struct {
+	name string
+	tmpl *template.Template
+}
+
+// newIssueFormatter parses a --format value: json, md, text or template=<Go template>.
+func newIssueFormatter(format string) (*issueFormatter, error) {
+	if text, ok := strings.CutPrefix(format, "template="); ok {
+		tmpl, err := template.New("format").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --format template: %w", err)
+		}
+		return &issueFormatter{name: "template", tmpl: tmpl}, nil
+	}
+
+	switch format {
+	case "json", "md", "text":
+		return &issueFormatter{name: format}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected json, md, text or template=<template>", format)
+	}
+}
+
+// Write writes issues to w. A single issue is written on its own, and as an object rather than an array in JSON.
+func (f *issueFormatter) Write(w io.Writer, issues []tracker.Issue) error {
+	if f.name == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if len(issues) == 1 {
+			return encoder.Encode(issues[0])
+		}
+		return encoder.Encode(issues)
+	}
+
+	for i, issue := range issues {
+		if i > 0 && f.name == "md" {
+			fmt.Fprint(w, "\n")
+		} else if i > 0 {
+			fmt.Fprint(w, "\n\n")
+		}
+		if err := f.write(w, issue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *issueFormatter) write(w io.Writer, issue tracker.Issue) error {
+	switch f.name {
+	case "md":
+		_, err := fmt.Fprintf(w, "# %s\n\n%s\n\n%s\n", issue.Title, issue.URL, issue.Description)
+		return err
+	case "template":
+		return f.tmpl.Execute(w, issue)
+	default:
+		_, err := fmt.Fprint(w, issue.Description)
+		return err
+	}
+}
+
+// isTerminal reports whether f is connected to a terminal.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
@@ -1,146 +1,229 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"net/url"
 	"os"
 
-	"github.com/charmbracelet/huh"
+	"github.com/atotto/clipboard"
+	"github.com/nlgtEA/lazyai/fuzzy"
+	"github.com/nlgtEA/lazyai/picker"
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/nlgtEA/lazyai/tracker"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-var selectedState string = "started"
-
-// Story represents a Pivotal Tracker story
-type Story struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-	Desc string `json:"description"`
-	URL  string `json:"url"`
-}
-
-func (s Story) FilterValue() string {
-	return s.Name
-}
+var (
+	pickID     string
+	pickFirst  bool
+	pickQuery  string
+	pickFormat string
+)
 
 // pickPTCmd represents the pickPT command
 var pickPTCmd = &cobra.Command{
 	Use:   "pickPT",
-	Short: "Retrieve the description of your active Pivotal Tracker story",
-	Long: `The pickPT command helps you quickly find the Pivotal Tracker story you are currently working on (in 'started' state) and returns its description.
+	Short: "Retrieve the description of your active story",
+	Long: `The pickPT command helps you quickly find the story you are currently working on and returns its description.
 This command streamlines your workflow by providing instant access to essential story details.
 
+Stories come from Pivotal Tracker (stories in 'started' state), from Jira (issues matching jira.jql)
+or from the GitHub Issues of the current repository (open issues assigned to you), as selected by the tracker configuration key or the --tracker flag.
+
 Configuration:
 Ensure your configuration file (~/.lazyai.yml) is set up properly with the following details:
 
+    tracker: pivotal
     pivotalTracker:
         apiToken: <your_api_token>
-        projectID: <project_ID>
-        owner: <your_account_name, e.g. thuanngo>
-`,
+        projectID: <project_ID, optional, defaults to all your projects>
+        owner: <your_account_name, e.g. thuanngo, optional, defaults to the owner of the API token>
 
-	PreRunE: func(cmd *cobra.Command, args []string) error {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("error finding home directory: %w", err)
-		}
+or, for Jira:
 
-		viper.SetConfigName(".lazyai")
-		viper.SetConfigType("yaml")
-		viper.AddConfigPath(home)
+    tracker: jira
+    jira:
+        baseURL: https://<your-site>.atlassian.net
+        email: <your Atlassian account email, omit for Jira Server>
+        apiToken: <your API token or personal access token>
 
-		if err := viper.ReadInConfig(); err != nil {
-			return fmt.Errorf("error reading config file: %w", err)
-		}
+or, for GitHub Issues (the repository is detected from the origin remote):
 
-		apiToken := viper.GetString("pivotalTracker.apiToken")
-		projectID := viper.GetString("pivotalTracker.projectID")
-		owner := viper.GetString("pivotalTracker.owner")
+    tracker: github
+    github:
+        token: <your personal access token, or set GITHUB_TOKEN>
+        user: <your login, optional when a token is set>
 
-		if apiToken == "" || projectID == "" || owner == "" {
-			return fmt.Errorf("apiToken, projectID and owner must be set in the configuration file.\nPlease check your ~/.lazyai.yml again!\n")
-		}
+Picking:
+Type to filter the stories by name, label or ID. The highlighted story is previewed on the right.
 
-		cmd.Flags().String("apiToken", apiToken, "Pivotal Tracker API token")
-		cmd.Flags().String("projectID", projectID, "Pivotal Tracker project ID")
-		cmd.Flags().String("owner", owner, "Pivotal Tracker owner user")
+    enter     print the story
+    ctrl+y    copy the link of the story
+    ctrl+o    open the story in the browser
+    ctrl+s    start the story (Pivotal Tracker only)
+    esc       cancel
 
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		apiToken, _ := cmd.Flags().GetString("apiToken")
-		projectID, _ := cmd.Flags().GetString("projectID")
-		owner, _ := cmd.Flags().GetString("owner")
+Filters:
+Pivotal Tracker stories can be filtered with flags, or with the pivotalTracker settings of the same name:
 
-		link, _ := cmd.Flags().GetBool("link")
+    # Unstarted bugs and chores of two projects, in the current iteration
+    lazyai pickPT --project 123456,234567 --state unstarted --type bug,chore --iteration current
 
-		// Make HTTP request to Pivotal Tracker API
-		baseURL := "https://www.pivotaltracker.com/services/v5/projects/" + projectID + "/stories"
-		queryParams := url.Values{}
-		queryParams.Add("filter", fmt.Sprintf("owner:\"%s\" AND state:\"%s\"", owner, selectedState))
-		encodedURL := fmt.Sprintf("%s?%s", baseURL, queryParams.Encode())
+    pivotalTracker:
+        projectIDs: [123456, 234567]
+        states: [started, finished]
+        labels: [backend]
+        filter: 'created_since:"last week"'
 
-		req, err := http.NewRequest("GET", encodedURL, nil)
-		if err != nil {
-			log.Fatalf("Failed to create request: %v", err)
-		}
+Scripting:
+When stdin or stdout is not a terminal, no story is asked for and every active story is written.
 
-		req.Header.Set("X-TrackerToken", apiToken)
+    # The description of the only story you are working on
+    lazyai pickPT --first
 
-		client := &http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			log.Fatalf("Failed to make request: %v", err)
-		}
-		defer resp.Body.Close()
+    # A specific story, as JSON
+    lazyai pickPT --id 187654321 --format json
 
-		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			log.Fatalf("Failed to get stories: %s", string(bodyBytes))
+    # The ID of the active story matching "login"
+    lazyai pickPT --query login --first --format 'template={{.ID}}'
+`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		// Bound here rather than in init, so that the flags do not override the settings of other commands
+		if err := bindPickFlags(cmd); err != nil {
+			return err
 		}
-
-		bodyBytes, err := io.ReadAll(resp.Body)
+		return requireSettings(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := pickFormat
+		if link, _ := cmd.Flags().GetBool("link"); link {
+			format = "template={{.URL}}"
+		}
+		formatter, err := newIssueFormatter(format)
 		if err != nil {
-			log.Fatalf("Failed to read response body: %v", err)
+			return err
 		}
 
-		var stories []Story
-		err = json.Unmarshal(bodyBytes, &stories)
+		t, err := newTracker(cmd.Context())
 		if err != nil {
-			log.Fatalf("Failed to unmarshal response: %v", err)
+			return err
 		}
 
-		myOptions := make([]huh.Option[string], len(stories))
+		if pickID != "" {
+			issue, err := t.Get(cmd.Context(), pickID)
+			if err != nil {
+				return fmt.Errorf("failed to get story %s: %w", pickID, err)
+			}
+			return formatter.Write(os.Stdout, []tracker.Issue{*issue})
+		}
 
-		for i, story := range stories {
-			if link != true {
-				myOptions[i] = huh.NewOption(story.Name, story.Desc)
-			} else {
-				myOptions[i] = huh.NewOption(story.Name, story.URL)
+		issues, err := t.ListMine(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get stories: %w", err)
+		}
+		if pickQuery != "" {
+			issues = fuzzy.Filter(issues, pickQuery, fuzzy.IssueText)
+			if len(issues) == 0 {
+				return fmt.Errorf("no active stories match %q", pickQuery)
 			}
 		}
+		if len(issues) == 0 {
+			return errors.New("no active stories found")
+		}
 
-		var desc string
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[string]().
-					Title("Pick a story.").
-					Options(myOptions...).
-					Value(&desc),
-			),
-		)
-
-		form.Run()
-		fmt.Print(desc)
+		switch {
+		case pickFirst:
+			issues = issues[:1]
+		case isTerminal(os.Stdin) && isTerminal(os.Stdout):
+			picked, err := picker.Run(issues, pickerActions(cmd)...)
+			if err != nil {
+				return err
+			}
+			issues = issues[picked : picked+1]
+		}
+		// Without a terminal, every matching story is written
+		return formatter.Write(os.Stdout, issues)
 	},
 }
 
+// pickerActions returns the actions available on the highlighted story of the picker.
+func pickerActions(cmd *cobra.Command) []picker.Action {
+	actions := []picker.Action{
+		{Key: "ctrl+y", Help: "copy link", Run: func(issue tracker.Issue) (tracker.Issue, string, error) {
+			if err := clipboard.WriteAll(issue.URL); err != nil {
+				return issue, "", err
+			}
+			return issue, "Copied " + issue.URL, nil
+		}},
+		{Key: "ctrl+o", Help: "open", Run: func(issue tracker.Issue) (tracker.Issue, string, error) {
+			return issue, "Opened " + issue.URL, openURL(issue.URL)
+		}},
+	}
+
+	if trackerName() == "pivotal" {
+		actions = append(actions, picker.Action{Key: "ctrl+s", Help: "start", Run: func(issue tracker.Issue) (tracker.Issue, string, error) {
+			client := pivotalClient()
+			story, err := getStory(cmd, client, issue.ID)
+			if err != nil {
+				return issue, "", err
+			}
+			updated, err := moveStory(cmd.Context(), client, story, pivotal.Started)
+			if err != nil {
+				return issue, "", err
+			}
+			started := tracker.StoryIssue(*updated)
+			started.Project = issue.Project
+			return started, fmt.Sprintf("Started #%d", story.ID), nil
+		}})
+	}
+	return actions
+}
+
 func init() {
 	rootCmd.AddCommand(pickPTCmd)
 	pickPTCmd.Flags().BoolP("link", "l", false, "Returns only the link of the story")
+	pickPTCmd.Flags().StringVar(&pickID, "id", "", "Fetch the story with this ID instead of picking one")
+	pickPTCmd.Flags().BoolVar(&pickFirst, "first", false, "Pick the first active story without asking")
+	pickPTCmd.Flags().StringVarP(&pickQuery, "query", "q", "", "Only offer the active stories whose ID, title or labels fuzzily match this text")
+	pickPTCmd.Flags().StringVarP(&pickFormat, "format", "f", "text", "Output format: json, md, text (the description) or template=<Go template>, e.g. template={{.ID}}")
+	pickPTCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"json", "md", "text", "template="}, cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace))
+	pickPTCmd.MarkFlagsMutuallyExclusive("link", "format")
+	pickPTCmd.MarkFlagsMutuallyExclusive("id", "first")
+	pickPTCmd.MarkFlagsMutuallyExclusive("id", "query")
+	pickPTCmd.Flags().BoolVar(&refreshCache, "refresh", false, "Fetch Pivotal Tracker stories again instead of revalidating the cached ones")
+	pickPTCmd.Flags().String("tracker", "", "Tracker to pick the story from: pivotal, jira or github (default \"pivotal\")")
+	pickPTCmd.RegisterFlagCompletionFunc("tracker", cobra.FixedCompletions(trackerNames, cobra.ShellCompDirectiveNoFileComp))
+
+	// Pivotal Tracker filters, overriding the pivotalTracker settings of the same name
+	pickPTCmd.Flags().StringSlice("project", nil, "IDs of the projects to list stories of")
+	pickPTCmd.Flags().StringSlice("state", nil, "States of the stories to list (default started)")
+	pickPTCmd.Flags().StringSlice("type", nil, "Types of the stories to list: feature, bug, chore, release")
+	pickPTCmd.Flags().StringSlice("label", nil, "Labels of the stories to list, any of them")
+	pickPTCmd.Flags().String("iteration", "", "Iteration of the stories to list: current, backlog or icebox")
+	pickPTCmd.Flags().String("filter", "", "Tracker search query the stories must also match, e.g. 'created_since:\"last week\"'")
+	pickPTCmd.RegisterFlagCompletionFunc("state", cobra.FixedCompletions([]string{pivotal.Unscheduled, pivotal.Unstarted, pivotal.Planned, pivotal.Started, pivotal.Finished, pivotal.Delivered, pivotal.Rejected, pivotal.Accepted}, cobra.ShellCompDirectiveNoFileComp))
+	pickPTCmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions([]string{pivotal.Feature, pivotal.Bug, pivotal.Chore, pivotal.Release}, cobra.ShellCompDirectiveNoFileComp))
+	pickPTCmd.RegisterFlagCompletionFunc("iteration", cobra.FixedCompletions([]string{pivotal.CurrentIteration, pivotal.Backlog, pivotal.Icebox}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// pickFlagKeys maps the flags of pickPT to the settings they override.
+var pickFlagKeys = map[string]string{
+	"tracker":   "tracker",
+	"project":   "pivotalTracker.projectIDs",
+	"state":     "pivotalTracker.states",
+	"type":      "pivotalTracker.types",
+	"label":     "pivotalTracker.labels",
+	"iteration": "pivotalTracker.iteration",
+	"filter":    "pivotalTracker.filter",
+}
+
+// bindPickFlags makes the flags of pickPT override the settings of the same name.
+func bindPickFlags(cmd *cobra.Command) error {
+	for flag, key := range pickFlagKeys {
+		if err := viper.BindPFlag(key, cmd.Flags().Lookup(flag)); err != nil {
+			return fmt.Errorf("error binding --%s: %w", flag, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nlgtEA/lazyai/cache"
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/spf13/viper"
+)
+
+// meCacheTTL is how long the user and projects an API token belongs to are cached.
+const meCacheTTL = 24 * time.Hour
+
+// refreshCache makes Tracker requests ignore the cached responses, see --refresh.
+var refreshCache bool
+
+var staleWarning sync.Once
+
+func pivotalClient() *pivotal.Client {
+	token := viper.GetString("pivotalTracker.apiToken")
+	client := pivotal.NewClient(token)
+	client.Cache = responseCache{token}
+	client.Refresh = refreshCache
+	client.OnStale = func(fetchedAt time.Time, err error) {
+		staleWarning.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: %v\nShowing Pivotal Tracker data cached %s ago.\n", err, time.Since(fetchedAt).Round(time.Minute))
+		})
+	}
+	return client
+}
+
+// responseCache keeps Tracker responses in the cache directory, apart for each API token.
+type responseCache struct {
+	token string
+}
+
+func (c responseCache) name(key string) string {
+	sum := sha256.Sum256([]byte(c.token + "\n" + key))
+	return fmt.Sprintf("pivotal/%x.json", sum[:16])
+}
+
+func (c responseCache) Get(key string) (*pivotal.CachedResponse, bool) {
+	var response pivotal.CachedResponse
+	_, ok, _ := cache.Load(c.name(key), &response)
+	return &response, ok
+}
+
+func (c responseCache) Put(key string, response *pivotal.CachedResponse) {
+	// Failing to cache only costs a request next time
+	cache.Save(c.name(key), response)
+}
+
+// pivotalMe returns the Tracker user the API token belongs to, from the cache when it is recent enough.
+func pivotalMe(ctx context.Context) (*pivotal.Me, error) {
+	token := viper.GetString("pivotalTracker.apiToken")
+	// Entries are keyed by a hash of the token, so that switching profiles switches users
+	sum := sha256.Sum256([]byte(token))
+	name := fmt.Sprintf("pivotal-me-%x.json", sum[:8])
+
+	var me pivotal.Me
+	if saved, ok, _ := cache.Load(name, &me); ok && !refreshCache && time.Since(saved) < meCacheTTL {
+		return &me, nil
+	}
+
+	fetched, err := pivotalClient().Me(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get your Pivotal Tracker account: %w", err)
+	}
+	if err := cache.Save(name, fetched); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return fetched, nil
+}
+
+// pivotalOwner returns pivotalTracker.owner, or else the username of the user the API token belongs to.
+func pivotalOwner(ctx context.Context) (string, error) {
+	if owner := viper.GetString("pivotalTracker.owner"); owner != "" {
+		return owner, nil
+	}
+	me, err := pivotalMe(ctx)
+	if err != nil {
+		return "", err
+	}
+	return me.Username, nil
+}
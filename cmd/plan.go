@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/nlgtEA/lazyai/prompt"
+	"github.com/nlgtEA/lazyai/skydeck"
+	"github.com/spf13/cobra"
+)
+
+// repoMapLines is the length above which directories of the repository map are collapsed.
+const repoMapLines = 300
+
+var pickPlanStory bool
+
+var planCmd = &cobra.Command{
+	Use:   "plan [story]",
+	Short: "Ask the AI for a plan to implement a story",
+	Long: `Ask the AI for a plan to implement a story in the current repository. The story, with its acceptance
+criteria, tasks and comments, is sent with a map of the files of the repository, and the plan is
+streamed into a new conversation bound to the story.
+
+When no story is given, the current story is used, see "lazyai pt current", or you pick one of
+your started stories. Continue the conversation with "lazyai sdchat --scope story:<ID>".
+
+Files ignored by .lazyaiignore are left out of the map.
+
+Examples:
+    # Plan the current story
+    lazyai plan
+
+    # Plan another story, then ask for the code of the first step
+    lazyai plan 187654321
+    lazyai sdchat --scope story:187654321 "Write the code of step 1"
+`,
+	Args: cobra.MaximumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireCommandSettings("plan"); err != nil {
+			return err
+		}
+		return loadState(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := pivotalClient()
+		story, err := planStory(cmd, client, args)
+		if err != nil {
+			return err
+		}
+		// Owners and commenters are shown by their ID when the members cannot be listed
+		people, _ := client.Members(cmd.Context(), story.ProjectID)
+
+		root, err := git.RepoRoot()
+		if err != nil {
+			return err
+		}
+		ignore, err := git.LoadIgnoreRules(root)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", git.IgnoreFile, err)
+		}
+		repoMap, err := git.RepoMap(ignore, repoMapLines)
+		if err != nil {
+			return err
+		}
+
+		message, err := prompt.Plan(prompt.PlanData{
+			Story: strings.TrimSpace(pivotal.Markdown(story, people)),
+			Repo:  filepath.Base(root),
+			Map:   repoMap,
+		})
+		if err != nil {
+			return fmt.Errorf("error rendering plan prompt: %w", err)
+		}
+		warnIfOverBudget(message)
+
+		fmt.Fprintf(os.Stderr, "Planning story #%d: %s\n\n", story.ID, story.Name)
+		key := scopeKey(storyScope(story.ID))
+		convoID, err := newSkydeckClient().Chat(skydeck.NewMessage(message, modelID(), nil), os.Stdout)
+		if convoID != 0 {
+			bindConversation(key, convoID)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "\n\nContinue with: lazyai sdchat --scope %s\n", storyScope(story.ID))
+		return nil
+	},
+	ValidArgsFunction: cobra.NoFileCompletions,
+}
+
+func init() {
+	planCmd.Flags().BoolVarP(&pickPlanStory, "pick", "p", false, "Pick the story among your started stories instead of using the current one")
+	rootCmd.AddCommand(planCmd)
+}
+
+// storyScope returns the conversation scope of a story.
+func storyScope(storyID int) string {
+	return fmt.Sprintf("story:%d", storyID)
+}
+
+// planStory returns the story given as argument, or else the current story, or else the one the user picks.
+func planStory(cmd *cobra.Command, client *pivotal.Client, args []string) (*pivotal.Story, error) {
+	if len(args) == 1 {
+		return getStory(cmd, client, args[0])
+	}
+	if !pickPlanStory {
+		if current, ok := resolveStory(defaultBaseRef("origin")); ok {
+			return getStory(cmd, client, fmt.Sprint(current.ID))
+		}
+	}
+	return pickStory(cmd, client, []string{pivotal.Started}, true, nil)
+}
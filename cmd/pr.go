@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/github"
+	"github.com/nlgtEA/lazyai/prompt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	prBase   string
+	prRemote string
+	prDraft  bool
+	prPush   bool
+	prDryRun bool
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Generate a pull request description for the current branch and open the pull request",
+	Long: `The pr command sends the commits and the diff of the current branch against its base branch to SkyDeck,
+lets you edit the generated title and description in your $EDITOR and then opens the pull request on GitHub.
+
+The first line of the edited draft is used as the title, the rest as the description.
+
+Configuration:
+The pull request is created through the GitHub REST API when a token is available,
+otherwise the gh CLI is used. The token is read from the GITHUB_TOKEN or GH_TOKEN
+environment variables or from the ~/.lazyai.yml configuration file:
+
+github:
+    token: <your personal access token>
+    apiURL: <optional, defaults to https://api.github.com>
+
+Examples:
+    # Open a pull request against the default branch of origin
+    lazyai pr
+
+    # Push the branch first and open a draft pull request against develop
+    lazyai pr --push --draft --base develop
+
+    # Print the title and description without opening the pull request
+    lazyai pr --dry-run
+`,
+	Args:    cobra.NoArgs,
+	PreRunE: requireSettings,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		head, err := git.CurrentBranch()
+		if err != nil {
+			return err
+		}
+
+		base := prBase
+		if base == "" {
+			base = git.DefaultBranch(prRemote)
+		}
+		if head == base {
+			return fmt.Errorf("you are on the base branch %q, switch to a feature branch first", base)
+		}
+
+		// Without --push, the branch must already be on the remote for the pull request to be opened
+		prHead := head
+		if !prPush && !prDryRun {
+			if prHead, err = pushedBranch(prRemote, head); err != nil {
+				return err
+			}
+		}
+
+		data, diff, err := prPromptData(prRemote, base)
+		if err != nil {
+			return err
+		}
+
+		apiClient := newSkydeckClient()
+		data.Diff, data.Summaries, err = condenseDiff(apiClient, diff, data.Log)
+		if err != nil {
+			return err
+		}
+
+		message, err := prompt.PR(data)
+		if err != nil {
+			return fmt.Errorf("error rendering pull request prompt: %w", err)
+		}
+
+		draft, err := reviewDraft(apiClient, message, "lazyai-pr-*.md", strings.TrimSpace)
+		if err != nil {
+			return err
+		}
+
+		title, body := splitTitle(draft)
+		if title == "" {
+			return errors.New("aborting due to empty title")
+		}
+
+		if prDryRun {
+			fmt.Printf("%s\n\n%s\n", title, body)
+			return nil
+		}
+
+		if prPush {
+			if _, err := git.Run("push", "--set-upstream", prRemote, "HEAD"); err != nil {
+				return err
+			}
+		}
+
+		return createPR(github.NewPullRequest{
+			Title: title,
+			Head:  prHead,
+			Base:  base,
+			Body:  body,
+			Draft: prDraft,
+		})
+	},
+}
+
+func init() {
+	prCmd.Flags().StringVarP(&prBase, "base", "B", "", "Base branch of the pull request (defaults to the remote's default branch)")
+	prCmd.Flags().StringVar(&prRemote, "remote", "origin", "Remote the pull request is opened against")
+	prCmd.Flags().BoolVarP(&prDraft, "draft", "d", false, "Open the pull request as a draft")
+	prCmd.Flags().BoolVar(&prPush, "push", false, "Push the current branch before opening the pull request")
+	prCmd.Flags().BoolVar(&prDryRun, "dry-run", false, "Print the title and description instead of opening the pull request")
+
+	rootCmd.AddCommand(prCmd)
+}
+
+// prPromptData collects the commits and changes of the current branch that are not on base yet.
+func prPromptData(remote, base string) (prompt.PRData, *git.Diff, error) {
+	baseRef := base
+	if git.HasCommit(remote + "/" + base) {
+		baseRef = remote + "/" + base
+	}
+
+	log, err := git.Log(baseRef + "..HEAD")
+	if err != nil {
+		return prompt.PRData{}, nil, err
+	}
+	diff, err := git.CollectDiff(git.DiffOptions{Base: baseRef + "...HEAD"})
+	if err != nil {
+		return prompt.PRData{}, nil, err
+	}
+	if diff.IsEmpty() {
+		return prompt.PRData{}, nil, fmt.Errorf("no changes between %s and HEAD", baseRef)
+	}
+
+	data := prompt.PRData{Base: base, Log: log, Diff: diff.Patch(), Excluded: diff.ExcludedSummary()}
+	data.StoryID, data.StoryURL = currentStory(baseRef)
+	return data, diff, nil
+}
+
+// pushedBranch returns the name of the branch on remote that the current branch is pushed to, or an
+// error when it has no upstream on remote or commits that are not pushed yet.
+func pushedBranch(remote, head string) (string, error) {
+	upstream, err := git.Upstream()
+	if err != nil || !strings.HasPrefix(upstream, remote+"/") {
+		return "", fmt.Errorf("branch %q is not pushed to %s, push it first or run \"lazyai pr --push\"", head, remote)
+	}
+	unpushed, err := git.CountCommits(upstream + "..HEAD")
+	if err != nil {
+		return "", err
+	}
+	if unpushed > 0 {
+		return "", fmt.Errorf("%d commit(s) of %q are not pushed to %s yet, push them first or run \"lazyai pr --push\"", unpushed, head, upstream)
+	}
+	return strings.TrimPrefix(upstream, remote+"/"), nil
+}
+
+// splitTitle splits a draft into its first line, used as the title, and the remaining body.
+func splitTitle(draft string) (string, string) {
+	title, body, _ := strings.Cut(strings.TrimSpace(draft), "\n")
+	title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+	title = strings.TrimSpace(strings.TrimPrefix(title, "Title:"))
+	return title, strings.TrimSpace(body)
+}
+
+func githubToken() string {
+	for _, env := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := os.Getenv(env); token != "" {
+			return token
+		}
+	}
+	return viper.GetString("github.token")
+}
+
+func createPR(pr github.NewPullRequest) error {
+	token := githubToken()
+	if token == "" {
+		return createPRWithGH(pr)
+	}
+
+	remoteURL, err := git.RemoteURL(prRemote)
+	if err != nil {
+		return err
+	}
+	owner, repo, err := github.ParseRemote(remoteURL)
+	if err != nil {
+		return err
+	}
+
+	client := github.NewClient(viper.GetString("github.apiURL"), token)
+	created, err := client.CreatePullRequest(owner, repo, pr)
+	if err != nil {
+		return fmt.Errorf("error creating pull request: %w", err)
+	}
+
+	fmt.Println(created.HTMLURL)
+	return nil
+}
+
+func createPRWithGH(pr github.NewPullRequest) error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return errors.New("no GitHub token configured and the gh CLI is not installed")
+	}
+
+	args := []string{"pr", "create", "--title", pr.Title, "--body-file", "-", "--base", pr.Base, "--head", pr.Head}
+	if pr.Draft {
+		args = append(args, "--draft")
+	}
+
+	cmd := exec.Command("gh", args...)
+	cmd.Stdin = strings.NewReader(pr.Body)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/nlgtEA/lazyai/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	profileName  string
+	clearProfile bool
+)
+
+var profileCmd = &cobra.Command{
+	Use:               "profile",
+	PersistentPreRunE: loadSettingsLenient,
+	Short:             "List and switch between configuration profiles",
+	Long: `Profiles let you keep several sets of settings, e.g. for a company SkyDeck tenant and a personal account,
+in the ~/.lazyai.yml configuration file. The settings of the active profile override the top-level ones:
+
+skydeck:
+    accessToken: <personal access token>
+    refreshToken: <personal refresh token>
+profiles:
+    work:
+        skydeck:
+            accessToken: <company access token>
+            refreshToken: <company refresh token>
+            modelID: 4094
+        pivotalTracker:
+            apiToken: <your_api_token>
+            projectID: <project_ID>
+            owner: <your_account_name>
+
+The active profile is selected with the --profile flag, the LAZYAI_PROFILE environment variable
+or "lazyai profile use", in that order of precedence.
+`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the profiles of the configuration file",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		names := profileNames()
+		if len(names) == 0 {
+			fmt.Println("No profiles configured.")
+			return
+		}
+
+		active := activeProfile()
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "\tPROFILE\tSECTIONS")
+		for _, name := range names {
+			marker := ""
+			if name == active {
+				marker = "*"
+			}
+
+			var sections []string
+			for section := range viper.GetStringMap("profiles." + name) {
+				sections = append(sections, section)
+			}
+			sort.Strings(sections)
+			fmt.Fprintf(w, "%s\t%s\t%v\n", marker, name, sections)
+		}
+		w.Flush()
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <profile>",
+	Short: "Make a profile the default one",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if clearProfile {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		doc, err := config.LoadDocument(configFile())
+		if err != nil {
+			return err
+		}
+
+		if clearProfile {
+			doc.Unset("profile")
+			fmt.Println("Using the top-level settings by default")
+			return doc.Save()
+		}
+
+		name := args[0]
+		if !viper.IsSet("profiles." + name) {
+			return fmt.Errorf("unknown profile %q, see \"lazyai profile list\"", name)
+		}
+		if err := doc.Set("profile", name); err != nil {
+			return err
+		}
+		fmt.Printf("Using profile %q by default\n", name)
+		return doc.Save()
+	},
+	ValidArgsFunction: completeProfiles,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Configuration profile to use (default $LAZYAI_PROFILE or the profile set by \"lazyai profile use\")")
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+
+	profileUseCmd.Flags().BoolVar(&clearProfile, "none", false, "Stop using a profile by default")
+
+	profileCmd.AddCommand(profileListCmd, profileUseCmd)
+	rootCmd.AddCommand(profileCmd)
+}
+
+// activeProfile returns the name of the selected profile, or an empty string when none is selected.
+func activeProfile() string {
+	if profileName != "" {
+		return profileName
+	}
+	// Also covers the LAZYAI_PROFILE environment variable
+	return viper.GetString("profile")
+}
+
+// applyProfile merges the settings of the active profile over the top-level settings.
+func applyProfile() error {
+	name := activeProfile()
+	if name == "" {
+		return nil
+	}
+
+	settings := viper.Sub("profiles." + name)
+	if settings == nil {
+		return fmt.Errorf("%w %q, see \"lazyai profile list\"", errUnknownProfile, name)
+	}
+	return viper.MergeConfigMap(settings.AllSettings())
+}
+
+func profileNames() []string {
+	var names []string
+	for name := range viper.GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	loadConfig()
+	return profileNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// configFile returns the path of the configuration file in use.
+func configFile() string {
+	if configPath != "" {
+		return configPath
+	}
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path
+	}
+	path, err := config.DefaultPath()
+	cobra.CheckErr(err)
+	return path
+}
+
+// saveSetting writes a setting to the configuration file. When the active
+// profile defines the setting, the profile's value is updated instead of the top-level one.
+func saveSetting(key string, value any) error {
+	if name := activeProfile(); name != "" && viper.IsSet("profiles."+name+"."+key) {
+		key = "profiles." + name + "." + key
+	}
+	return config.Set(configFile(), key, value)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/prompt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptPattern  string
+	promptStaged   bool
+	promptUnstaged bool
+	promptBase     string
+	promptEdit     bool
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a prompt template for a task, a commit message or a pull request",
+	Long: `The prompt command renders one of the built-in prompt templates so that it can be edited or piped into sdchat.
+
+Available templates: code, commit, pr
+
+The commit template describes the staged changes when there are any, and all changes to tracked files otherwise.
+Files matching the patterns in the .lazyaiignore file at the repository root, lockfiles, vendored code and
+binary files are listed by name instead of being included in the diff.
+
+Examples:
+    # Ask for a commit message describing the unstaged changes
+    lazyai prompt -p commit --unstaged | lazyai sdchat -n
+
+    # Edit the pull request template before printing it
+    lazyai prompt -p pr --edit
+`,
+	Args:    cobra.NoArgs,
+	PreRunE: loadSettings,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var message string
+		var err error
+
+		switch promptPattern {
+		case "code":
+			message, err = prompt.Code()
+		case "commit":
+			message, err = commitPrompt()
+		case "pr":
+			message, err = prPrompt()
+		default:
+			return fmt.Errorf("unknown template %q, available templates: code, commit, pr", promptPattern)
+		}
+		if err != nil {
+			return err
+		}
+
+		warnIfOverBudget(message)
+
+		if promptEdit {
+			if message, err = editText(message, "lazyai-prompt-*.md"); err != nil {
+				return err
+			}
+		}
+
+		fmt.Print(message)
+		return nil
+	},
+}
+
+func init() {
+	promptCmd.Flags().StringVarP(&promptPattern, "pattern", "p", "code", "Template to render: code, commit or pr")
+	promptCmd.Flags().BoolVar(&promptStaged, "staged", false, "Describe only the staged changes")
+	promptCmd.Flags().BoolVar(&promptUnstaged, "unstaged", false, "Describe only the changes that are not staged yet")
+	promptCmd.Flags().StringVar(&promptBase, "base", "", "Base branch the pr template compares against (defaults to the remote's default branch)")
+	promptCmd.Flags().BoolVarP(&promptEdit, "edit", "e", false, "Open the rendered prompt in your $EDITOR before printing it")
+	promptCmd.MarkFlagsMutuallyExclusive("staged", "unstaged")
+	promptCmd.RegisterFlagCompletionFunc("pattern", cobra.FixedCompletions([]string{"code", "commit", "pr"}, cobra.ShellCompDirectiveNoFileComp))
+
+	rootCmd.AddCommand(promptCmd)
+}
+
+func commitPrompt() (string, error) {
+	var diff *git.Diff
+	var err error
+
+	switch {
+	case promptStaged:
+		diff, err = git.CollectDiff(git.StagedDiff())
+	case promptUnstaged:
+		diff, err = git.CollectDiff(git.UnstagedDiff())
+	default:
+		if diff, err = git.CollectDiff(git.StagedDiff()); err == nil && diff.IsEmpty() {
+			diff, err = git.CollectDiff(git.WorkingTreeDiff())
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if diff.IsEmpty() {
+		return "", errors.New("there are no changes to describe")
+	}
+
+	data := prompt.CommitData{Diff: diff.Patch(), Excluded: diff.ExcludedSummary()}
+	data.StoryID, data.StoryURL = currentStory(defaultBaseRef("origin"))
+	return prompt.Commit(data)
+}
+
+func prPrompt() (string, error) {
+	base := promptBase
+	if base == "" {
+		base = git.DefaultBranch("origin")
+	}
+
+	data, _, err := prPromptData("origin", base)
+	if err != nil {
+		return "", err
+	}
+	return prompt.PR(data)
+}
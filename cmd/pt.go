@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/nlgtEA/lazyai/picker"
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/nlgtEA/lazyai/tracker"
+	"github.com/spf13/cobra"
+)
+
+var ptCmd = &cobra.Command{
+	Use:   "pt",
+	Short: "Work with Pivotal Tracker stories",
+	Long: `The pt command changes Pivotal Tracker stories without leaving the terminal.
+
+Stories are given by ID, with or without a leading #. When no story is given, you are asked
+to pick one of the stories the command applies to.
+
+Examples:
+    # Start a story picked among the unstarted ones
+    lazyai pt start
+
+    # Finish, then deliver a story
+    lazyai pt finish 187654321
+    lazyai pt deliver 187654321
+`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireCommandSettings("pt")
+	},
+}
+
+// ptTransitions maps the pt subcommands to the state they move stories to.
+var ptTransitions = []struct {
+	action, state, short string
+}{
+	{"start", pivotal.Started, "Start a story"},
+	{"finish", pivotal.Finished, "Finish a started feature or bug"},
+	{"deliver", pivotal.Delivered, "Deliver a finished feature or bug"},
+	{"accept", pivotal.Accepted, "Accept a delivered story, or a started chore or release"},
+	{"reject", pivotal.Rejected, "Reject a delivered feature or bug"},
+}
+
+func init() {
+	ptCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "Fetch stories from Tracker again instead of revalidating the cached ones")
+	for _, t := range ptTransitions {
+		state := t.state
+		ptCmd.AddCommand(&cobra.Command{
+			Use:   t.action + " [story]",
+			Short: t.short,
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return transitionStory(cmd, args, state)
+			},
+			ValidArgsFunction: cobra.NoFileCompletions,
+		})
+	}
+
+	rootCmd.AddCommand(ptCmd)
+}
+
+func transitionStory(cmd *cobra.Command, args []string, state string) error {
+	client := pivotalClient()
+
+	var story *pivotal.Story
+	var err error
+	if len(args) == 1 {
+		story, err = getStory(cmd, client, args[0])
+	} else {
+		story, err = pickTransitionStory(cmd, client, state)
+	}
+	if err != nil {
+		return err
+	}
+
+	updated, err := moveStory(cmd.Context(), client, story, state)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("#%d %s is now %s\n%s\n", updated.ID, updated.Name, updated.CurrentState, updated.URL)
+	return nil
+}
+
+// pickTransitionStory asks the user to pick one of the stories that can be moved to state. Stories are
+// finished and delivered by their owners, started by their owner or by anyone when nobody owns them yet,
+// and accepted or rejected by anyone.
+func pickTransitionStory(cmd *cobra.Command, client *pivotal.Client, state string) (*pivotal.Story, error) {
+	canMove := func(story *pivotal.Story) bool {
+		return pivotal.CanTransition(story, state)
+	}
+
+	switch state {
+	case pivotal.Finished, pivotal.Delivered:
+		return pickStory(cmd, client, pivotal.FromStates(state), true, canMove)
+	case pivotal.Started:
+		available, err := availableStories(cmd.Context())
+		if err != nil {
+			return nil, err
+		}
+		return pickStory(cmd, client, pivotal.FromStates(state), false, func(story *pivotal.Story) bool {
+			return canMove(story) && available(story)
+		})
+	default:
+		return pickStory(cmd, client, pivotal.FromStates(state), false, canMove)
+	}
+}
+
+// availableStories returns a filter keeping the stories owned by the user, and the stories nobody owns
+// that are not started yet.
+func availableStories(ctx context.Context) (func(*pivotal.Story) bool, error) {
+	me, err := pivotalMe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return func(story *pivotal.Story) bool {
+		if slices.Contains(story.OwnerIDs, me.ID) {
+			return true
+		}
+		return len(story.OwnerIDs) == 0 && slices.Contains(pivotal.FromStates(pivotal.Started), story.CurrentState)
+	}, nil
+}
+
+// moveStory moves a story to state, when its type and current state allow it.
+func moveStory(ctx context.Context, client *pivotal.Client, story *pivotal.Story, state string) (*pivotal.Story, error) {
+	if err := pivotal.CheckTransition(story, state); err != nil {
+		return nil, err
+	}
+	updated, err := client.UpdateStory(ctx, story.ProjectID, story.ID, pivotal.StoryUpdate{CurrentState: &state})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update story #%d: %w", story.ID, err)
+	}
+	return updated, nil
+}
+
+// getStory fetches a story by its ID, with or without a leading #.
+func getStory(cmd *cobra.Command, client *pivotal.Client, ref string) (*pivotal.Story, error) {
+	storyID, err := tracker.StoryID(ref)
+	if err != nil {
+		return nil, err
+	}
+	story, err := client.StoryByID(cmd.Context(), storyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get story #%d: %w", storyID, err)
+	}
+	return story, nil
+}
+
+// pickStory asks the user to pick one of the stories in the given states, owned by the user when mine is set.
+// When keep is not nil, only the stories it returns true for are offered.
+func pickStory(cmd *cobra.Command, client *pivotal.Client, states []string, mine bool, keep func(*pivotal.Story) bool) (*pivotal.Story, error) {
+	if !isTerminal(os.Stdin) {
+		return nil, errors.New("no story given, pass the story ID")
+	}
+
+	filter := pivotal.StoryFilter{States: states}
+	if mine {
+		owner, err := pivotalOwner(cmd.Context())
+		if err != nil {
+			return nil, err
+		}
+		filter.Owner = owner
+	}
+	projectIDs, err := pivotalProjectIDs(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	var stories []pivotal.Story
+	for _, projectID := range projectIDs {
+		found, err := client.Stories(cmd.Context(), projectID, filter.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stories: %w", err)
+		}
+		for _, story := range found {
+			if keep == nil || keep(&story) {
+				stories = append(stories, story)
+			}
+		}
+	}
+	if len(stories) == 0 {
+		return nil, errors.New("no stories to pick from, pass the story ID")
+	}
+
+	issues := make([]tracker.Issue, len(stories))
+	for i, story := range stories {
+		issues[i] = tracker.StoryIssue(story)
+	}
+	picked, err := picker.Run(issues)
+	if err != nil {
+		return nil, err
+	}
+	return &stories[picked], nil
+}
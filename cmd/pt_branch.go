@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultBranchTemplate names branches like feature/187654321-add-the-login-page.
+const defaultBranchTemplate = "{{.Type}}/{{.ID}}-{{.Slug}}"
+
+// maxSlugLength keeps branch names readable when story names are long.
+const maxSlugLength = 50
+
+var (
+	branchWorktree string
+	startStory     bool
+)
+
+var ptBranchCmd = &cobra.Command{
+	Use:   "branch [story]",
+	Short: "Create a git branch, or a worktree, for a story",
+	Long: `Create and check out a git branch named after a story, picked among your started stories and
+the unstarted ones that are yours or nobody's when not given.
+
+The branch name is built from the pivotalTracker.branchTemplate configuration key, a Go template
+over the story's ID, Type, Name and Slug (the name in lowercase words joined by dashes):
+
+    pivotalTracker:
+        branchTemplate: "{{.Type}}/{{.ID}}-{{.Slug}}"   # the default
+
+Examples:
+    # Pick a story, create its branch and start the story
+    lazyai pt branch --start
+
+    # Work on a story in a new worktree next to the repository
+    lazyai pt branch 187654321 --worktree ../lazyai-login
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := pivotalClient()
+
+		var story *pivotal.Story
+		var err error
+		if len(args) == 1 {
+			story, err = getStory(cmd, client, args[0])
+		} else {
+			// Started stories are the user's, the others may not be owned by anyone yet
+			var available func(*pivotal.Story) bool
+			if available, err = availableStories(cmd.Context()); err != nil {
+				return err
+			}
+			story, err = pickStory(cmd, client, []string{pivotal.Unstarted, pivotal.Planned, pivotal.Rejected, pivotal.Started}, false, available)
+		}
+		if err != nil {
+			return err
+		}
+
+		name, err := storyBranchName(story)
+		if err != nil {
+			return err
+		}
+		if startStory && story.CurrentState != pivotal.Started {
+			// Refuse early, before a branch is left behind for a story that cannot start
+			if err := pivotal.CheckTransition(story, pivotal.Started); err != nil {
+				return err
+			}
+		}
+
+		create := !git.BranchExists(name)
+		if branchWorktree != "" {
+			err = git.AddWorktree(branchWorktree, name, create)
+		} else {
+			err = git.Checkout(name, create)
+		}
+		if err != nil {
+			return err
+		}
+		if branchWorktree != "" {
+			fmt.Printf("Checked out %s in %s\n", name, branchWorktree)
+		} else {
+			fmt.Printf("Switched to %s\n", name)
+		}
+
+		if startStory && story.CurrentState != pivotal.Started {
+			if _, err := moveStory(cmd.Context(), client, story, pivotal.Started); err != nil {
+				return err
+			}
+			fmt.Printf("#%d %s is now started\n", story.ID, story.Name)
+		}
+		return nil
+	},
+	ValidArgsFunction: cobra.NoFileCompletions,
+}
+
+func init() {
+	ptBranchCmd.Flags().StringVarP(&branchWorktree, "worktree", "w", "", "Check the branch out in a new worktree at this path instead")
+	ptBranchCmd.Flags().BoolVar(&startStory, "start", false, "Move the story to started")
+	ptCmd.AddCommand(ptBranchCmd)
+}
+
+// storyBranchName renders the branch template for a story.
+func storyBranchName(story *pivotal.Story) (string, error) {
+	text := viper.GetString("pivotalTracker.branchTemplate")
+	if text == "" {
+		text = defaultBranchTemplate
+	}
+	tmpl, err := template.New("branch").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid pivotalTracker.branchTemplate: %w", err)
+	}
+
+	var name strings.Builder
+	data := map[string]string{
+		"ID":   strconv.Itoa(story.ID),
+		"Type": story.StoryType,
+		"Name": story.Name,
+		"Slug": slugify(story.Name),
+	}
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("invalid pivotalTracker.branchTemplate: %w", err)
+	}
+
+	branch := strings.Trim(filepath.ToSlash(name.String()), "/-")
+	if _, err := git.Run("check-ref-format", "--branch", branch); err != nil {
+		return "", fmt.Errorf("invalid branch name %q, check pivotalTracker.branchTemplate", branch)
+	}
+	return branch, nil
+}
+
+// slugify turns a story name into lowercase words joined by dashes.
+func slugify(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+
+	var slug string
+	for _, word := range words {
+		next := word
+		if slug != "" {
+			next = slug + "-" + word
+		}
+		if len(next) > maxSlugLength && slug != "" {
+			break
+		}
+		slug = next
+	}
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+	}
+	return slug
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "words joined by dashes",
+			text: "Add the Login Page",
+			want: "add-the-login-page",
+		},
+		{
+			name: "punctuation and spaces collapsed",
+			text: "  Fix: crash on upload (iOS 17)!  ",
+			want: "fix-crash-on-upload-ios-17",
+		},
+		{
+			name: "non-ASCII letters dropped",
+			text: "Traduire le résumé",
+			want: "traduire-le-r-sum",
+		},
+		{
+			name: "cut at a word boundary",
+			text: "Let users reset their password from the login page when they forget it",
+			want: "let-users-reset-their-password-from-the-login-page",
+		},
+		{
+			name: "single long word truncated",
+			text: strings.Repeat("a", 60),
+			want: strings.Repeat("a", maxSlugLength),
+		},
+		{
+			name: "nothing left",
+			text: "🚀 !!",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slugify(tt.text); got != tt.want {
+				t.Errorf("slugify(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/nlgtEA/lazyai/tracker"
+	"github.com/spf13/cobra"
+)
+
+var commentAttachments []string
+
+var ptCommentCmd = &cobra.Command{
+	Use:   "comment [story] [text|-]",
+	Short: "Post a comment to a story",
+	Long: `Post a comment to a story. The text is read from stdin when it is "-" or left out and stdin is not a terminal.
+When no story is given, the current story is used, see "lazyai pt current". A single argument is
+the text, unless it has the #123456 form of a story, in which case the text is read from stdin.
+
+Examples:
+    # Comment on the current story
+    lazyai pt comment "Waiting for the API keys"
+
+    # Post a summary of the conversation of the current branch
+    lazyai sdchat "Summarize what we changed for the story" | lazyai pt comment -
+
+    # Attach a screenshot to a specific story
+    lazyai pt comment 187654321 "Before and after" --attach before.png --attach after.png
+
+    # Post the notes of a file to a specific story
+    lazyai pt comment '#187654321' < notes.md
+`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storyID, args, err := commentStory(args)
+		if err != nil {
+			return err
+		}
+
+		text, err := commentText(args)
+		if err != nil {
+			return err
+		}
+		if text == "" && len(commentAttachments) == 0 {
+			return errors.New("nothing to post, give the comment text or attach files")
+		}
+
+		client := pivotalClient()
+		story, err := client.StoryByID(cmd.Context(), storyID)
+		if err != nil {
+			return fmt.Errorf("failed to get story #%d: %w", storyID, err)
+		}
+
+		comment := pivotal.NewComment{Text: text}
+		for _, path := range commentAttachments {
+			attachment, err := uploadFile(cmd, client, story.ProjectID, path)
+			if err != nil {
+				return err
+			}
+			comment.FileAttachments = append(comment.FileAttachments, *attachment)
+		}
+
+		if _, err := client.CreateComment(cmd.Context(), story.ProjectID, storyID, comment); err != nil {
+			return fmt.Errorf("failed to comment on story #%d: %w", storyID, err)
+		}
+		fmt.Fprintf(os.Stderr, "Commented on story #%d\n", storyID)
+		return nil
+	},
+	ValidArgsFunction: cobra.NoFileCompletions,
+}
+
+func init() {
+	ptCommentCmd.Flags().StringArrayVarP(&commentAttachments, "attach", "a", nil, "Attach a file to the comment, can be repeated")
+	ptCmd.AddCommand(ptCommentCmd)
+}
+
+// commentStory takes the story from the first of two arguments, or from a single argument of the
+// #123456 form, and from the current branch otherwise, so that a lone text such as "42" is not
+// mistaken for a story. It returns the remaining arguments.
+func commentStory(args []string) (int, []string, error) {
+	if len(args) == 2 || (len(args) == 1 && strings.HasPrefix(args[0], "#")) {
+		storyID, err := tracker.StoryID(args[0])
+		if err != nil {
+			return 0, nil, err
+		}
+		return storyID, args[1:], nil
+	}
+
+	story, ok := resolveStory(defaultBaseRef("origin"))
+	if !ok {
+		return 0, nil, errors.New("no story found for the current work, pass the story ID")
+	}
+	return story.ID, args, nil
+}
+
+// commentText returns the text argument, or stdin when it is "-" or missing and stdin is not a terminal.
+func commentText(args []string) (string, error) {
+	if len(args) == 1 && args[0] != "-" {
+		return strings.TrimSpace(args[0]), nil
+	}
+	if len(args) == 0 && isTerminal(os.Stdin) {
+		return "", nil
+	}
+
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("error reading from stdin: %w", err)
+	}
+	return strings.TrimSpace(string(input)), nil
+}
+
+func uploadFile(cmd *cobra.Command, client *pivotal.Client, projectID int, path string) (*pivotal.FileAttachment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	attachment, err := client.Upload(cmd.Context(), projectID, filepath.Base(path), f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s: %w", path, err)
+	}
+	return attachment, nil
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/nlgtEA/lazyai/prompt"
+	"github.com/spf13/cobra"
+)
+
+// unestimated is the estimate option leaving a story without estimate.
+const unestimated = -1
+
+var createProjectID int
+
+var ptCreateCmd = &cobra.Command{
+	Use:   "create [notes...]",
+	Short: "Create a story from rough notes",
+	Long: `Create a story from rough notes. The AI turns the notes into a title, a description with
+acceptance criteria, a story type and an estimate, which you review and edit before the story is
+added to the icebox. The notes are read from stdin when they are "-" or left out and stdin is not a terminal.
+
+Examples:
+    # Describe a story in a few words
+    lazyai pt create "login page: email + password, remember me, rate limit failed attempts"
+
+    # Turn meeting notes into a story
+    lazyai pt create - < notes.md
+`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireCommandSettings("pt create")
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notes, err := createNotes(args)
+		if err != nil {
+			return err
+		}
+		if notes == "" {
+			return errors.New("no notes given, pass them as arguments or through stdin")
+		}
+
+		client := pivotalClient()
+		project, err := createProject(cmd, client)
+		if err != nil {
+			return err
+		}
+
+		points := project.Points()
+		scale := make([]string, len(points))
+		for i, point := range points {
+			scale[i] = strconv.FormatFloat(point, 'f', -1, 64)
+		}
+		message, err := prompt.Story(prompt.StoryData{Notes: notes, Points: strings.Join(scale, ", ")})
+		if err != nil {
+			return fmt.Errorf("error rendering story prompt: %w", err)
+		}
+		warnIfOverBudget(message)
+
+		fmt.Fprintln(os.Stderr, "Drafting story...")
+		answer, err := newSkydeckClient().Ask(message, modelID())
+		if err != nil {
+			return err
+		}
+		draft, err := parseStoryDraft(answer)
+		if err != nil {
+			return err
+		}
+
+		story, ok, err := reviewStory(draft, project)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("aborted, the story was not created")
+		}
+
+		created, err := client.CreateStory(cmd.Context(), project.ID, story)
+		if err != nil {
+			return fmt.Errorf("failed to create the story: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Created story #%d in %s\n", created.ID, project.Name)
+		fmt.Println(created.URL)
+		return nil
+	},
+	ValidArgsFunction: cobra.NoFileCompletions,
+}
+
+func init() {
+	ptCreateCmd.Flags().IntVar(&createProjectID, "project", 0, "Project to create the story in, picked among the configured projects by default")
+	ptCmd.AddCommand(ptCreateCmd)
+}
+
+// storyDraft is the story the AI drafted from the notes.
+type storyDraft struct {
+	Title              string   `json:"title"`
+	Description        string   `json:"description"`
+	AcceptanceCriteria []string `json:"acceptance_criteria"`
+	StoryType          string   `json:"story_type"`
+	Estimate           *float64 `json:"estimate"`
+}
+
+// createNotes returns the notes given as arguments, or read from stdin.
+func createNotes(args []string) (string, error) {
+	if len(args) > 0 && !(len(args) == 1 && args[0] == "-") {
+		return strings.TrimSpace(strings.Join(args, "\n")), nil
+	}
+	if len(args) == 0 && isTerminal(os.Stdin) {
+		return "", nil
+	}
+
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("error reading from stdin: %w", err)
+	}
+	return strings.TrimSpace(string(input)), nil
+}
+
+// createProject returns the project given with --project, the only configured project, or the one the user picks.
+func createProject(cmd *cobra.Command, client *pivotal.Client) (*pivotal.Project, error) {
+	if createProjectID != 0 {
+		return client.Project(cmd.Context(), createProjectID)
+	}
+
+	projectIDs, err := pivotalProjectIDs(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	if len(projectIDs) == 0 {
+		return nil, errors.New("you are not a member of any Pivotal Tracker project")
+	}
+
+	projects := make([]*pivotal.Project, len(projectIDs))
+	for i, id := range projectIDs {
+		if projects[i], err = client.Project(cmd.Context(), id); err != nil {
+			return nil, fmt.Errorf("failed to get project %d: %w", id, err)
+		}
+	}
+	if len(projects) == 1 {
+		return projects[0], nil
+	}
+
+	options := make([]huh.Option[*pivotal.Project], len(projects))
+	for i, project := range projects {
+		options[i] = huh.NewOption(project.Name, project)
+	}
+	var picked *pivotal.Project
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[*pivotal.Project]().
+			Title("Project to create the story in").
+			Options(options...).
+			Value(&picked),
+	)).WithProgramOptions(formInput()...)
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+	return picked, nil
+}
+
+// parseStoryDraft decodes the JSON object the AI answered with.
+func parseStoryDraft(answer string) (*storyDraft, error) {
+	answer = stripFence(answer)
+	// Skip any text around the object
+	if start, end := strings.Index(answer, "{"), strings.LastIndex(answer, "}"); start >= 0 && end > start {
+		answer = answer[start : end+1]
+	}
+
+	var draft storyDraft
+	if err := json.Unmarshal([]byte(answer), &draft); err != nil {
+		return nil, fmt.Errorf("error parsing the drafted story: %w", err)
+	}
+	return &draft, nil
+}
+
+// reviewStory lets the user edit the drafted story and returns it, or false when they abort.
+func reviewStory(draft *storyDraft, project *pivotal.Project) (pivotal.NewStory, bool, error) {
+	description := strings.TrimSpace(draft.Description)
+	if len(draft.AcceptanceCriteria) > 0 {
+		description += "\n\n## Acceptance Criteria\n"
+		for _, criterion := range draft.AcceptanceCriteria {
+			description += "\n- " + strings.TrimSpace(criterion)
+		}
+	}
+
+	storyType := draft.StoryType
+	if !slices.Contains([]string{pivotal.Feature, pivotal.Bug, pivotal.Chore}, storyType) {
+		storyType = pivotal.Feature
+	}
+
+	points := project.Points()
+	estimate := float64(unestimated)
+	if draft.Estimate != nil && slices.Contains(points, *draft.Estimate) {
+		estimate = *draft.Estimate
+	}
+	estimates := []huh.Option[float64]{huh.NewOption("Unestimated", float64(unestimated))}
+	for _, point := range points {
+		estimates = append(estimates, huh.NewOption(strconv.FormatFloat(point, 'f', -1, 64), point))
+	}
+
+	title := strings.TrimSpace(draft.Title)
+	confirmed := true
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Title").
+				Value(&title).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("the title cannot be empty")
+					}
+					return nil
+				}),
+			huh.NewText().
+				Title("Description").
+				Lines(12).
+				Value(&description),
+			huh.NewSelect[string]().
+				Title("Type").
+				Options(
+					huh.NewOption("Feature", pivotal.Feature),
+					huh.NewOption("Bug", pivotal.Bug),
+					huh.NewOption("Chore", pivotal.Chore),
+				).
+				Value(&storyType),
+		),
+		huh.NewGroup(
+			huh.NewSelect[float64]().
+				Title("Estimate").
+				Options(estimates...).
+				Value(&estimate),
+		).WithHideFunc(func() bool {
+			return storyType != pivotal.Feature && !project.BugsAndChoresAreEstimatable
+		}),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Create the story in %s?", project.Name)).
+				Value(&confirmed),
+		),
+	).WithProgramOptions(formInput()...)
+	if err := form.Run(); err != nil {
+		return pivotal.NewStory{}, false, err
+	}
+
+	story := pivotal.NewStory{
+		Name:        strings.TrimSpace(title),
+		Description: strings.TrimSpace(description),
+		StoryType:   storyType,
+	}
+	if estimate != unestimated && (storyType == pivotal.Feature || project.BugsAndChoresAreEstimatable) {
+		story.Estimate = &estimate
+	}
+	return story, confirmed, nil
+}
+
+// formInput reads forms from the terminal when stdin was used for the input.
+func formInput() []tea.ProgramOption {
+	if isTerminal(os.Stdin) {
+		return nil
+	}
+	return []tea.ProgramOption{tea.WithInputTTY()}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/nlgtEA/lazyai/tracker"
+	"github.com/spf13/cobra"
+)
+
+var (
+	currentSet   string
+	currentClear bool
+	currentURL   bool
+)
+
+var ptCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Print the ID of the story the current branch is about",
+	Long: `Print the ID of the story the current work is about. It is found, in this order, in:
+
+    1. the story pinned on the current branch with "lazyai pt current --set <story>", kept in .git/lazyai-story
+    2. the branch name, e.g. feature/187654321-login-page
+    3. the commits not on the default branch of origin yet, e.g. "Add the login page [#187654321]" or "[Finishes #187654321]".
+       They are not searched when the default branch of origin cannot be found.
+
+The same story is given to the commit and pr prompts, so that they can reference it.
+`,
+	Args: cobra.NoArgs,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Finding the story needs no Tracker access
+		return loadConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if currentClear {
+			return markStory(0)
+		}
+		if currentSet != "" {
+			id, err := tracker.StoryID(currentSet)
+			if err != nil {
+				return err
+			}
+			return markStory(id)
+		}
+
+		story, ok := resolveStory(defaultBaseRef("origin"))
+		if !ok {
+			return errors.New("no story found for the current work, see \"lazyai pt current --help\"")
+		}
+		if currentURL {
+			fmt.Println(pivotal.StoryURL(story.ID))
+		} else {
+			fmt.Println(story.ID)
+		}
+		if isTerminal(os.Stderr) {
+			fmt.Fprintf(os.Stderr, "from %s\n", story.Source)
+		}
+		return nil
+	},
+}
+
+func init() {
+	ptCurrentCmd.Flags().StringVar(&currentSet, "set", "", "Pin the story of the current branch")
+	ptCurrentCmd.Flags().BoolVar(&currentClear, "clear", false, "Unpin the story")
+	ptCurrentCmd.Flags().BoolVar(&currentURL, "url", false, "Print the URL of the story instead of its ID")
+	ptCurrentCmd.MarkFlagsMutuallyExclusive("set", "clear")
+	ptCmd.AddCommand(ptCurrentCmd)
+}
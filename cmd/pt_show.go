@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/spf13/cobra"
+)
+
+var showFormat string
+
+var ptShowCmd = &cobra.Command{
+	Use:   "show [story]",
+	Short: "Show a story with its tasks, blockers and comments",
+	Long: `Show a story with its estimate, owners, labels, acceptance criteria, tasks, blockers and comments.
+When no story is given, the current story is shown, see "lazyai pt current".
+
+The story is styled for the terminal, or written as Markdown with --format md or when
+stdout is not a terminal, ready to be piped into sdchat.
+
+Examples:
+    lazyai pt show 187654321
+
+    # Ask how to implement the current story
+    (lazyai pt show; echo; echo "How should I implement this story?") | lazyai sdchat -n
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := showFormat
+		if format == "" {
+			format = "md"
+			if isTerminal(os.Stdout) {
+				format = "styled"
+			}
+		}
+		if format != "md" && format != "styled" {
+			return fmt.Errorf("unknown format %q, expected md or styled", format)
+		}
+
+		ref := ""
+		if len(args) == 1 {
+			ref = args[0]
+		} else if story, ok := resolveStory(defaultBaseRef("origin")); ok {
+			ref = fmt.Sprint(story.ID)
+		} else {
+			return errors.New("no story found for the current work, pass the story ID")
+		}
+
+		client := pivotalClient()
+		story, err := getStory(cmd, client, ref)
+		if err != nil {
+			return err
+		}
+		// Owners and commenters are shown by their ID when the members cannot be listed
+		people, _ := client.Members(cmd.Context(), story.ProjectID)
+
+		if format == "md" {
+			fmt.Print(pivotal.Markdown(story, people))
+			return nil
+		}
+		fmt.Print(styledStory(story, people))
+		return nil
+	},
+	ValidArgsFunction: cobra.NoFileCompletions,
+}
+
+func init() {
+	ptShowCmd.Flags().StringVarP(&showFormat, "format", "f", "", "Output format: styled or md (default styled in a terminal, md otherwise)")
+	ptShowCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"styled", "md"}, cobra.ShellCompDirectiveNoFileComp))
+	ptCmd.AddCommand(ptShowCmd)
+}
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	headingStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+	labelStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("10")).Padding(0, 1)
+	dimStyle     = lipgloss.NewStyle().Faint(true)
+	doneStyle    = lipgloss.NewStyle().Faint(true).Strikethrough(true)
+	stateColors  = map[string]lipgloss.Color{
+		pivotal.Started:   "11",
+		pivotal.Finished:  "14",
+		pivotal.Delivered: "13",
+		pivotal.Accepted:  "10",
+		pivotal.Rejected:  "9",
+	}
+)
+
+// wrapWidth is the width long texts are wrapped at.
+const wrapWidth = 100
+
+// styledStory renders a story for the terminal.
+func styledStory(story *pivotal.Story, people []pivotal.Person) string {
+	var b strings.Builder
+	heading := func(title string) {
+		fmt.Fprintf(&b, "\n%s\n", headingStyle.Render(title))
+	}
+
+	fmt.Fprintln(&b, titleStyle.Render(story.Name))
+
+	state := lipgloss.NewStyle().Bold(true).Foreground(stateColors[story.CurrentState]).Render(story.CurrentState)
+	fmt.Fprintf(&b, "%s  %s · %s · %s\n", dimStyle.Render(fmt.Sprintf("#%d", story.ID)), story.StoryType, story.EstimateText(), state)
+	if len(story.OwnerIDs) > 0 {
+		owners := make([]string, len(story.OwnerIDs))
+		for i, id := range story.OwnerIDs {
+			owners[i] = pivotal.PersonName(people, id)
+		}
+		fmt.Fprintf(&b, "Owners: %s\n", strings.Join(owners, ", "))
+	}
+	if len(story.Labels) > 0 {
+		labels := make([]string, len(story.Labels))
+		for i, name := range story.LabelNames() {
+			labels[i] = labelStyle.Render(name)
+		}
+		fmt.Fprintln(&b, strings.Join(labels, " "))
+	}
+	fmt.Fprintln(&b, dimStyle.Render(story.URL))
+
+	section := func(title, text string) {
+		if text == "" {
+			return
+		}
+		heading(title)
+		fmt.Fprintln(&b, ansi.Wrap(text, wrapWidth, ""))
+	}
+
+	description, criteria := pivotal.SplitAcceptanceCriteria(story.Description)
+	section("Description", description)
+	section("Acceptance Criteria", criteria)
+
+	if len(story.Tasks) > 0 {
+		heading("Tasks")
+		for _, task := range story.Tasks {
+			if task.Complete {
+				fmt.Fprintln(&b, "[x] "+doneStyle.Render(task.Description))
+			} else {
+				fmt.Fprintln(&b, "[ ] "+task.Description)
+			}
+		}
+	}
+
+	if len(story.Blockers) > 0 {
+		heading("Blockers")
+		for _, blocker := range story.Blockers {
+			if blocker.Resolved {
+				fmt.Fprintln(&b, "• "+doneStyle.Render(blocker.Description))
+			} else {
+				fmt.Fprintln(&b, "• "+blocker.Description)
+			}
+		}
+	}
+
+	if len(story.Comments) > 0 {
+		heading("Comments")
+		for _, comment := range story.Comments {
+			if strings.TrimSpace(comment.Text) == "" {
+				continue
+			}
+			author := lipgloss.NewStyle().Bold(true).Render(pivotal.PersonName(people, comment.PersonID))
+			fmt.Fprintf(&b, "%s %s\n", author, dimStyle.Render(comment.CreatedAt.Format("2006-01-02 15:04")))
+			for _, line := range strings.Split(ansi.Wrap(strings.TrimSpace(comment.Text), wrapWidth-2, ""), "\n") {
+				fmt.Fprintln(&b, "  "+line)
+			}
+		}
+	}
+
+	return b.String()
+}
@@ -4,15 +4,39 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"strings"
 
+	"github.com/nlgtEA/lazyai/config"
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/prompt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+var (
+	configPath     string
+	configLoaded   bool
+	repoConfigFile string
+)
+
+var errUnknownProfile = errors.New("unknown profile")
+
 var rootCmd = &cobra.Command{
 	Use:   "lazyai",
 	Short: "Your personal AiDD helper",
-	Long:  `An AiDD tools suite to help with all of your tasks`,
+	Long: `An AiDD tools suite to help with all of your tasks
+
+Every setting of the configuration file can be overridden with an environment variable named
+after its key, prefixed with LAZYAI_ and with dots replaced by underscores, e.g.
+LAZYAI_SKYDECK_ACCESSTOKEN or LAZYAI_PIVOTALTRACKER_PROJECTID.
+
+A .lazyai.yml file at the root of the current git repository is merged over your own configuration,
+so that a team can share settings such as the Pivotal Tracker project or the prompt templates.
+Secrets and profiles in that file are ignored.`,
 }
 
 func Execute() {
@@ -23,4 +47,113 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Configuration file (default ~/.lazyai.yml)")
+}
+
+// loadConfig reads the configuration file, applies the active profile and
+// binds the LAZYAI_* environment variables. Commands call it from their
+// PreRunE hooks, so that --help and commands that need no configuration
+// work without a configuration file. Only the first call does any work.
+func loadConfig() error {
+	if configLoaded {
+		return nil
+	}
+	configLoaded = true
+
+	viper.SetEnvPrefix("LAZYAI")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+	// AutomaticEnv only applies to keys viper knows about, bind all of them explicitly
+	for _, key := range config.Keys {
+		viper.BindEnv(key.Name)
+	}
+
+	if configPath != "" {
+		viper.SetConfigFile(configPath)
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("error finding home directory: %w", err)
+		}
+
+		viper.SetConfigName(".lazyai")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath(home)
+	}
+
+	// A missing file is reported by the commands that need its settings,
+	// and can be created by "lazyai config init"
+	if err := viper.ReadInConfig(); err != nil {
+		_, notFound := err.(viper.ConfigFileNotFoundError)
+		if !notFound && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+	}
+
+	profileErr := applyProfile()
+
+	if err := mergeRepoConfig(); err != nil {
+		return err
+	}
+	if err := applyPromptTemplates(); err != nil {
+		return err
+	}
+
+	return profileErr
+}
+
+// mergeRepoConfig merges the .lazyai.yml file at the root of the current git
+// repository over the user's configuration, ignoring the secrets it may contain.
+func mergeRepoConfig() error {
+	root, err := git.RepoRoot()
+	if err != nil {
+		return nil
+	}
+
+	path := config.RepoPath(root)
+	if path == viper.ConfigFileUsed() {
+		return nil
+	}
+
+	settings, dropped, err := config.LoadRepoSettings(path)
+	if err != nil {
+		return err
+	}
+	for _, key := range dropped {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s in %s, it can only be set in your own configuration file\n", key, path)
+	}
+	if settings == nil {
+		return nil
+	}
+
+	repoConfigFile = path
+	return viper.MergeConfigMap(settings)
+}
+
+// applyPromptTemplates replaces the built-in prompt templates by the ones configured under prompts.
+func applyPromptTemplates() error {
+	for _, name := range []string{"code", "commit", "pr"} {
+		if text := viper.GetString("prompts." + name); text != "" {
+			if err := prompt.Override(name, text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadSettings is a PreRunE hook for commands that use the configuration but require no particular setting.
+func loadSettings(cmd *cobra.Command, args []string) error {
+	return loadConfig()
+}
+
+// loadSettingsLenient is like loadSettings, but only warns about an unknown
+// profile, so that commands managing the configuration can be used to fix it.
+func loadSettingsLenient(cmd *cobra.Command, args []string) error {
+	err := loadConfig()
+	if errors.Is(err, errUnknownProfile) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
+	return err
 }
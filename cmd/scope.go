@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/spf13/viper"
+)
+
+const (
+	scopeGlobal = "global"
+	scopeRepo   = "repo"
+	scopeBranch = "branch"
+
+	defaultScope = scopeBranch
+)
+
+// scopeKey returns the key conversations are bound to for the given scope.
+// Besides global, repo and branch, any other value is used as an explicit key.
+// Outside a git repository, or on a detached HEAD, the key falls back to the next wider scope.
+func scopeKey(scope string) string {
+	if scope == "" {
+		scope = viper.GetString("skydeck.scope")
+	}
+	if scope == "" {
+		scope = defaultScope
+	}
+
+	switch scope {
+	case scopeGlobal:
+		return scopeGlobal
+	case scopeRepo, scopeBranch:
+	default:
+		return "key:" + scope
+	}
+
+	root, err := git.RepoRoot()
+	if err != nil {
+		return scopeGlobal
+	}
+	if scope == scopeRepo {
+		return "repo:" + root
+	}
+
+	branch, err := git.CurrentBranch()
+	if err != nil {
+		return "repo:" + root
+	}
+	return "branch:" + root + "@" + branch
+}
+
+// profileKeyPrefix starts the binding and alias keys of a profile, so that the conversations of one
+// SkyDeck account are not continued with the tokens of another.
+const profileKeyPrefix = "profile:"
+
+// profileKey returns the key under which a binding or alias of the active profile is stored.
+func profileKey(key string) string {
+	if name := activeProfile(); name != "" {
+		return profileKeyPrefix + name + "/" + key
+	}
+	return key
+}
+
+// profileEntries returns the bindings or aliases of the active profile, keyed without their profile prefix.
+func profileEntries(entries map[string]int) map[string]int {
+	prefix := profileKey("")
+	own := map[string]int{}
+	for key, id := range entries {
+		if prefix == "" {
+			if !strings.HasPrefix(key, profileKeyPrefix) {
+				own[key] = id
+			}
+		} else if rest, ok := strings.CutPrefix(key, prefix); ok {
+			own[rest] = id
+		}
+	}
+	return own
+}
+
+// boundConversation returns the conversation bound to key, or 0 when there is none.
+func boundConversation(key string) int {
+	return appState.Bindings[profileKey(key)]
+}
+
+// importLegacyConversation binds the conversation of the deprecated skydeck.convoID setting, where the
+// last conversation was kept before bindings existed, to the global scope. It is done once per conversation,
+// so that resetting the binding afterwards is not undone.
+func importLegacyConversation() {
+	id := viper.GetInt("skydeck.convoID")
+	if id == 0 || slices.Contains(appState.Imported, id) {
+		return
+	}
+
+	appState.Imported = append(appState.Imported, id)
+	if _, ok := appState.Bindings[profileKey(scopeGlobal)]; !ok {
+		appState.Bindings[profileKey(scopeGlobal)] = id
+		fmt.Fprintf(os.Stderr, "Conversation %d of skydeck.convoID is now bound to the global scope, continue it with --scope global\n", id)
+	}
+	if err := appState.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving conversation binding: %v\n", err)
+	}
+}
+
+// bindConversation binds key to the conversation and saves the state.
+func bindConversation(key string, conversationID int) {
+	appState.Bindings[profileKey(key)] = conversationID
+	if err := appState.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving conversation binding: %v\n", err)
+	}
+}
@@ -1,58 +1,25 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
-	"net/http"
-	"net/http/cookiejar"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
 
+	"github.com/nlgtEA/lazyai/skydeck"
+	"github.com/nlgtEA/lazyai/state"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-const (
-	BaseURL     = "https://admin.skydeck.ai"
-	ReferrerURL = "https://eastagile.skydeck.ai/"
-)
-
-type SendMessagePayload struct {
-	Message             string `json:"message"`
-	ModelID             int    `json:"model_id"`
-	ConversationID      *int   `json:"conversation_id,omitempty"`
-	RegenerateMessageID int    `json:"regenerate_message_id"`
-	NonAI               bool   `json:"non_ai"`
-}
-
-type SendMessageResponse struct {
-	Data struct {
-		ConversationID int `json:"conversation_id"`
-		Messages       []struct {
-			ID        int    `json:"id"`
-			Type      string `json:"type"`
-			Content   string `json:"content"`
-			Streaming bool   `json:"streaming"`
-		} `json:"messages"`
-	} `json:"data"`
-}
-
-type Config struct {
-	accessToken    string
-	currentConvoID int
-	refreshToken   string
-}
-
 var (
-	config         *Config
-	conversationID int
-	openInBrowser  bool
-	newConvo       bool
+	appState        *state.State
+	conversationRef string
+	scope           string
+	openInBrowser   bool
+	newConvo        bool
 )
 
 var sdchatCmd = &cobra.Command{
@@ -73,137 +40,77 @@ Examples:
     # Send a message to a specific conversation
     sdchat -c 123 "Continue our previous conversation."
 
+    # Send a message to a conversation by its alias, see "sdchat alias --help"
+    sdchat -c auth-refactor "Continue our previous conversation."
+
     # Send a message and open the conversation in the default browser
     sdchat -o "Hello, SkyDeck!"
+
+    # Continue the conversation shared by all branches of the current repository
+    sdchat --scope repo "What did we decide about the API?"
+
+Conversation scopes:
+Messages continue the conversation bound to the current scope, and the scope is bound to the
+conversation the message ends up in. The scope is one of:
+
+    global    one conversation everywhere
+    repo      one conversation per git repository
+    branch    one conversation per git repository and branch (the default)
+    <key>     any other value is used as an explicit key, e.g. --scope auth-refactor
+
+The default scope can be changed with the skydeck.scope configuration key. The conversation
+started before scopes existed, kept in skydeck.convoID, stays in the global scope.
+Use "sdchat bindings" to list the bindings and "sdchat bindings reset" to forget them.
 `,
+	PersistentPreRunE: loadState,
+	PreRunE:           requireSettings,
 	Run: func(cmd *cobra.Command, args []string) {
 		handleRun(cmd, args)
 	},
 }
 
 func init() {
-	cobra.OnInitialize(loadConfig)
-
-	sdchatCmd.Flags().IntVarP(&conversationID, "conversation", "c", 0, "Conversation ID to use for the message")
+	sdchatCmd.Flags().StringVarP(&conversationRef, "conversation", "c", "", "Conversation ID or alias to use for the message")
+	sdchatCmd.RegisterFlagCompletionFunc("conversation", completeAliases)
 	sdchatCmd.Flags().BoolVarP(&openInBrowser, "open", "o", false, "Open the conversation in the default browser instead of streaming the response to the terminal")
 	sdchatCmd.Flags().BoolVarP(&newConvo, "new", "n", false, "Chat in a new conversation")
+	sdchatCmd.PersistentFlags().StringVarP(&scope, "scope", "s", "", "Conversation scope: global, repo, branch or an explicit key (default \"branch\"); the conversation started before scopes existed stays in the global scope")
+	sdchatCmd.RegisterFlagCompletionFunc("scope", cobra.FixedCompletions([]string{scopeGlobal, scopeRepo, scopeBranch}, cobra.ShellCompDirectiveNoFileComp))
 
 	rootCmd.AddCommand(sdchatCmd)
 }
 
-func loadConfig() {
-	home, err := os.UserHomeDir()
-	cobra.CheckErr(err)
-
-	viper.SetConfigName(".lazyai")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(home)
-
-	viper.AutomaticEnv()
-
-	err = viper.ReadInConfig()
-	cobra.CheckErr(err)
+// loadState loads the conversation bindings and aliases, for sdchat and its subcommands.
+func loadState(cmd *cobra.Command, args []string) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
 
-	config = &Config{
-		accessToken:    viper.GetString("skydeck.accessToken"),
-		refreshToken:   viper.GetString("skydeck.refreshToken"),
-		currentConvoID: viper.GetInt("skydeck.convoID"),
+	var err error
+	if appState, err = state.Load(); err != nil {
+		return err
 	}
+	importLegacyConversation()
+	return nil
 }
 
 func updateAccessToken(newAccessToken string) error {
 	viper.Set("skydeck.accessToken", newAccessToken)
-	return viper.WriteConfig()
-}
-
-type APIClient struct {
-	Client       *http.Client
-	AccessToken  string
-	RefreshToken string
-}
-
-func NewAPIClient(accessToken, refreshToken string) *APIClient {
-	jar, _ := cookiejar.New(nil)
-	client := &http.Client{Jar: jar}
-
-	return &APIClient{
-		Client:       client,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}
+	return saveSetting("skydeck.accessToken", newAccessToken)
 }
 
-func (api *APIClient) sendMessage(payload SendMessagePayload) (*SendMessageResponse, error) {
-	url := BaseURL + "/api/v1/conversations/chat_v2/"
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	writeFormFields(writer, payload)
-	writer.Close()
-
-	req, err := http.NewRequest("POST", url, &buf)
-	if err != nil {
-		return nil, err
-	}
-
-	setRequestHeaders(req, api.AccessToken, api.RefreshToken, writer.FormDataContentType())
-	resp, err := api.Client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	return handleResponse(resp, api, payload)
-}
-
-func writeFormFields(writer *multipart.Writer, payload SendMessagePayload) {
-	writer.WriteField("message", payload.Message)
-	writer.WriteField("model_id", fmt.Sprintf("%d", payload.ModelID))
-	if payload.ConversationID != nil {
-		writer.WriteField("conversation_id", fmt.Sprintf("%d", *payload.ConversationID))
-	}
-	writer.WriteField("regenerate_message_id", fmt.Sprintf("%d", payload.RegenerateMessageID))
-	writer.WriteField("non_ai", fmt.Sprintf("%t", payload.NonAI))
-}
-
-func setRequestHeaders(req *http.Request, accessToken, refreshToken, contentType string) {
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("Referer", ReferrerURL)
-	req.AddCookie(&http.Cookie{Name: "eastagile_access", Value: accessToken})
-	req.AddCookie(&http.Cookie{Name: "eastagile_refresh", Value: refreshToken})
-}
-
-func handleResponse(resp *http.Response, api *APIClient, payload SendMessagePayload) (*SendMessageResponse, error) {
-	var response SendMessageResponse
-	if resp.StatusCode == http.StatusUnauthorized {
-		return handleUnauthorizedResponse(api, payload)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received non-200 response code: %d, body: %s", resp.StatusCode, readResponseBody(resp))
-	}
-
-	err := json.NewDecoder(resp.Body).Decode(&response)
-	if err != nil {
-		return nil, fmt.Errorf("error decoding response: %v", err)
-	}
-
-	return &response, nil
-}
-
-func handleUnauthorizedResponse(api *APIClient, payload SendMessagePayload) (*SendMessageResponse, error) {
-	newAccessToken, err := api.refreshTokens()
-	if err != nil {
-		return nil, fmt.Errorf("error refreshing tokens: %v", err)
+// modelID returns the SkyDeck model messages are sent to.
+func modelID() int {
+	if id := viper.GetInt("skydeck.modelID"); id != 0 {
+		return id
 	}
-	api.AccessToken = newAccessToken
-
-	return api.sendMessage(payload)
+	return skydeck.DefaultModelID
 }
 
-func readResponseBody(resp *http.Response) string {
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	return string(bodyBytes)
+func newSkydeckClient() *skydeck.APIClient {
+	apiClient := skydeck.NewAPIClient(viper.GetString("skydeck.accessToken"), viper.GetString("skydeck.refreshToken"))
+	apiClient.OnTokenRefresh = updateAccessToken
+	return apiClient
 }
 
 func handleRun(cmd *cobra.Command, args []string) {
@@ -229,11 +136,21 @@ func handleRun(cmd *cobra.Command, args []string) {
 
 	// Trim message to remove any trailing newlines or spaces
 	message = strings.TrimSpace(message)
+	warnIfOverBudget(message)
 
 	// Handle conversation
+	conversationID, err := resolveConversation(conversationRef)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	key := scopeKey(scope)
+	currentConvoID := boundConversation(key)
+
 	var conversationIDPtr *int
-	if config.currentConvoID != 0 {
-		conversationIDPtr = &config.currentConvoID
+	if currentConvoID != 0 {
+		conversationIDPtr = &currentConvoID
 	}
 	if conversationID != 0 {
 		conversationIDPtr = &conversationID
@@ -242,25 +159,18 @@ func handleRun(cmd *cobra.Command, args []string) {
 		conversationIDPtr = nil
 	}
 
-	payload := SendMessagePayload{
-		Message:             message,
-		ModelID:             4094,
-		ConversationID:      conversationIDPtr,
-		RegenerateMessageID: -1,
-		NonAI:               false,
-	}
+	payload := skydeck.NewMessage(message, modelID(), conversationIDPtr)
 
-	apiClient := NewAPIClient(config.accessToken, config.refreshToken)
-	resp, err := apiClient.sendMessage(payload)
+	apiClient := newSkydeckClient()
+	resp, err := apiClient.SendMessage(payload)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error sending message: %v\n", err)
 		return
 	}
 
 	convoID := getConversationID(conversationID, resp)
-	viper.Set("skydeck.convoID", convoID)
-	viper.WriteConfig()
-	conversationURL := fmt.Sprintf("https://eastagile.skydeck.ai/conversations/%d", convoID)
+	bindConversation(key, convoID)
+	conversationURL := skydeck.ConversationURL(convoID)
 
 	if openInBrowser {
 		if err := openURL(conversationURL); err != nil {
@@ -268,20 +178,13 @@ func handleRun(cmd *cobra.Command, args []string) {
 		}
 	} else {
 		// Find the assistant message ID in the response
-		var assistantMessageID int
-		for _, msg := range resp.Data.Messages {
-			if msg.Type == "assistant" && msg.Streaming {
-				assistantMessageID = msg.ID
-				break
-			}
-		}
-		
+		assistantMessageID := resp.AssistantMessageID()
 		if assistantMessageID == 0 {
 			fmt.Fprintf(os.Stderr, "Error: No streaming assistant message found in the response\n")
 			return
 		}
-		
-		err = apiClient.getStreamingResponse(assistantMessageID)
+
+		err = apiClient.StreamResponse(assistantMessageID, os.Stdout)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error getting streaming response: %v\n", err)
 			return
@@ -289,132 +192,13 @@ func handleRun(cmd *cobra.Command, args []string) {
 	}
 }
 
-func getConversationID(conversationID int, resp *SendMessageResponse) int {
+func getConversationID(conversationID int, resp *skydeck.SendMessageResponse) int {
 	if conversationID != 0 {
 		return conversationID
 	}
 	return resp.Data.ConversationID
 }
 
-type StreamingReq struct {
-	MessageID int `json:"message_id"`
-}
-
-type StreamingResponse struct {
-	Data struct {
-		ConversationID int `json:"conversation_id"`
-		Messages       []struct {
-			ID        int    `json:"id"`
-			Type      string `json:"type"`
-			Content   string `json:"content"`
-			Streaming bool   `json:"streaming"`
-		} `json:"messages"`
-	} `json:"data"`
-}
-
-func (api *APIClient) getStreamingResponse(messageID int) error {
-	url := BaseURL + fmt.Sprintf("/api/v1/conversations/streaming/")
-
-	payload := StreamingReq{
-		MessageID: messageID,
-	}
-
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %v", err)
-	}
-
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
-	}
-
-	setRequestHeaders(req, api.AccessToken, api.RefreshToken, "application/json")
-
-	resp, err := api.Client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusUnauthorized {
-		api.AccessToken, err = api.refreshTokens()
-		if err != nil {
-			return fmt.Errorf("error refreshing tokens: %v", err)
-		}
-
-		updateAccessToken(api.AccessToken)
-
-		req.AddCookie(&http.Cookie{Name: "eastagile_access", Value: api.AccessToken})
-		resp, err = api.Client.Do(req)
-		if err != nil {
-			return err
-		}
-		defer resp.Body.Close()
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("received non-200 response code: %d, body: %s", resp.StatusCode, readResponseBody(resp))
-	}
-
-	// The response might be the raw streaming content directly
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %v", err)
-	}
-	
-	bodyStr := string(bodyBytes)
-	
-	// Try to decode as JSON first
-	var streamResp StreamingResponse
-	if err := json.Unmarshal(bodyBytes, &streamResp); err == nil {
-		// If successful JSON decode, find the assistant message
-		for _, msg := range streamResp.Data.Messages {
-			if msg.Type == "assistant" && msg.Streaming {
-				fmt.Print(msg.Content)
-				return nil
-			}
-		}
-		return fmt.Errorf("no streaming assistant message found in the response")
-	}
-	
-	// If not valid JSON, output the raw response as it's likely the streamed content
-	fmt.Print(bodyStr)
-	return nil
-}
-
-func (api *APIClient) refreshTokens() (string, error) {
-	url := BaseURL + "/api/v1/authentication/token/refresh/"
-	req, err := http.NewRequest(http.MethodPost, url, nil)
-	if err != nil {
-		return "", err
-	}
-
-	req.AddCookie(&http.Cookie{Name: "eastagile_refresh", Value: api.RefreshToken})
-	req.Header.Set("Referer", ReferrerURL)
-
-	resp, err := api.Client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent {
-		return "", fmt.Errorf("received non-200 response code: %d, body: %s", resp.StatusCode, readResponseBody(resp))
-	}
-
-	return extractAccessTokenFromCookies(resp.Cookies()), nil
-}
-
-func extractAccessTokenFromCookies(cookies []*http.Cookie) string {
-	for _, cookie := range cookies {
-		if cookie.Name == "eastagile_access" {
-			return cookie.Value
-		}
-	}
-	return ""
-}
-
 func openURL(url string) error {
 	var err error
 
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/pivotal"
+)
+
+// storyMarker is the file in the git directory that pins the current story, see "lazyai pt current --set".
+const storyMarker = "lazyai-story"
+
+// storyCommitDepth is the number of commits searched for story references.
+const storyCommitDepth = 20
+
+// branchStoryPattern matches a Tracker story ID in a branch name, e.g. feature/187654321-login-page.
+var branchStoryPattern = regexp.MustCompile(`(?:^|[^0-9])#?([0-9]{6,})(?:[^0-9]|$)`)
+
+// commitStoryPattern matches the story references Tracker recognizes in commit messages, e.g. [#187654321] or [Finishes #187654321].
+var commitStoryPattern = regexp.MustCompile(`(?i)\[(?:(?:fix(?:e[sd])?|finish(?:e[sd])?|complete[sd]?|deliver(?:s|ed)?)\s+)?#([0-9]+)\]`)
+
+// storyRef is the story the current work is about, and where it was found.
+type storyRef struct {
+	ID     int
+	Source string
+}
+
+// resolveStory finds the current story in, by priority, the story marker, the
+// branch name and the messages of the commits between baseRef and HEAD. The
+// commits are not searched when baseRef is empty.
+func resolveStory(baseRef string) (storyRef, bool) {
+	if id, ok := markedStoryID(); ok {
+		return storyRef{id, "marker"}, true
+	}
+
+	if branch, err := git.CurrentBranch(); err == nil {
+		if id, ok := branchStoryID(branch); ok {
+			return storyRef{id, "branch " + branch}, true
+		}
+	}
+
+	if baseRef == "" {
+		// Without a base, the commits of other stories cannot be told apart from the ones of this branch
+		return storyRef{}, false
+	}
+	messages, _ := git.Messages(baseRef+"..HEAD", storyCommitDepth)
+	for _, message := range messages {
+		if m := commitStoryPattern.FindStringSubmatch(message); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			subject, _, _ := strings.Cut(message, "\n")
+			return storyRef{id, fmt.Sprintf("commit %q", subject)}, true
+		}
+	}
+	return storyRef{}, false
+}
+
+// defaultBaseRef returns the default branch of remote, e.g. origin/main, or "" when it cannot be found.
+func defaultBaseRef(remote string) string {
+	if base := remote + "/" + git.DefaultBranch(remote); git.HasCommit(base) {
+		return base
+	}
+	return ""
+}
+
+// currentStory returns the ID and URL of the current story, or zero values when there is none.
+func currentStory(baseRef string) (int, string) {
+	story, ok := resolveStory(baseRef)
+	if !ok {
+		return 0, ""
+	}
+	return story.ID, pivotal.StoryURL(story.ID)
+}
+
+// branchStoryID extracts a story ID from a branch name.
+func branchStoryID(branch string) (int, bool) {
+	m := branchStoryPattern.FindStringSubmatch(branch)
+	if m == nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(m[1])
+	return id, err == nil
+}
+
+// markedStoryID returns the pinned story, unless it was pinned on another branch.
+func markedStoryID() (int, bool) {
+	path, err := git.GitPath(storyMarker)
+	if err != nil {
+		return 0, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+
+	// The marker holds the story ID and the branch it was pinned on
+	id, branch, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	if strings.TrimSpace(branch) != markerBranch() {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(id), "#"))
+	return n, err == nil
+}
+
+// markStory pins the current story on the current branch, or unpins it when id is 0.
+func markStory(id int) error {
+	path, err := git.GitPath(storyMarker)
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(path, []byte(fmt.Sprintf("%d\n%s\n", id, markerBranch())), 0o644)
+}
+
+// markerBranch returns the branch the story marker applies to, "HEAD" when it is detached.
+func markerBranch() string {
+	branch, err := git.CurrentBranch()
+	if err != nil {
+		return "HEAD"
+	}
+	return branch
+}
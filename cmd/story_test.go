@@ -0,0 +1,57 @@
+package cmd
+
+import "testing"
+
+func TestBranchStoryID(t *testing.T) {
+	tests := []struct {
+		branch string
+		id     int
+		ok     bool
+	}{
+		{branch: "feature/187654321-login-page", id: 187654321, ok: true},
+		{branch: "187654321", id: 187654321, ok: true},
+		{branch: "bug/fix-login-#187654321", id: 187654321, ok: true},
+		{branch: "chore/187654321_bump-deps", id: 187654321, ok: true},
+		{branch: "feature/login-page", ok: false},
+		{branch: "release-2024", ok: false},
+		{branch: "v12345", ok: false},
+		{branch: "main", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			id, ok := branchStoryID(tt.branch)
+			if id != tt.id || ok != tt.ok {
+				t.Errorf("branchStoryID(%q) = %d, %v, want %d, %v", tt.branch, id, ok, tt.id, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCommitStoryPattern(t *testing.T) {
+	tests := []struct {
+		message string
+		id      string
+	}{
+		{message: "Add the login page [#187654321]", id: "187654321"},
+		{message: "[Finishes #187654321] Add the login page", id: "187654321"},
+		{message: "Fix the redirect\n\n[fixed #187654321]", id: "187654321"},
+		{message: "[Delivers #187654321] Deploy the login page", id: "187654321"},
+		{message: "[COMPLETES #187654321]", id: "187654321"},
+		{message: "Add the login page #187654321", id: ""},
+		{message: "[Starts #187654321] Add the login page", id: ""},
+		{message: "[#abc] Add the login page", id: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			id := ""
+			if m := commitStoryPattern.FindStringSubmatch(tt.message); m != nil {
+				id = m[1]
+			}
+			if id != tt.id {
+				t.Errorf("commitStoryPattern in %q = %q, want %q", tt.message, id, tt.id)
+			}
+		})
+	}
+}
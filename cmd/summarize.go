@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/config"
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/prompt"
+	"github.com/nlgtEA/lazyai/skydeck"
+	"github.com/spf13/viper"
+)
+
+// promptOverhead is the number of tokens reserved for the instructions around a diff.
+const promptOverhead = 1000
+
+// tokenBudget returns the maximum number of tokens of a single outgoing message.
+// Budgets too small to hold a diff next to the prompt are raised to config.MinTokenBudget.
+func tokenBudget() int {
+	if budget := viper.GetInt("skydeck.maxTokens"); budget > 0 {
+		return max(budget, config.MinTokenBudget)
+	}
+	return prompt.DefaultTokenBudget
+}
+
+// warnIfOverBudget prints a warning when message is likely too large for the model.
+func warnIfOverBudget(message string) {
+	if tokens, budget := prompt.EstimateTokens(message), tokenBudget(); tokens > budget {
+		fmt.Fprintf(os.Stderr, "Warning: the message is about %d tokens, more than the budget of %d tokens, and may be rejected or truncated\n", tokens, budget)
+	}
+}
+
+// condenseDiff returns the patch of diff when it fits in the token budget.
+// Otherwise the patch is split into chunks that are summarized in separate
+// conversations, and the joined summaries are returned instead. When the
+// summaries do not fit either, they are summarized again until they do.
+func condenseDiff(apiClient *skydeck.APIClient, diff *git.Diff, extra string) (patch, summaries string, err error) {
+	budget := tokenBudget() - promptOverhead
+	patch = diff.Patch()
+	if prompt.EstimateTokens(patch)+prompt.EstimateTokens(extra) <= budget {
+		return patch, "", nil
+	}
+
+	patches := make([]string, len(diff.Files))
+	for i, file := range diff.Files {
+		patches[i] = file.Patch
+	}
+	chunks := prompt.Chunk(patches, budget)
+
+	parts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		fmt.Fprintf(os.Stderr, "Diff exceeds the token budget, summarizing part %d of %d...\n", i+1, len(chunks))
+
+		message, err := prompt.Summary(prompt.SummaryData{Part: i + 1, Parts: len(chunks), Diff: chunk})
+		if err != nil {
+			return "", "", fmt.Errorf("error rendering summary prompt: %w", err)
+		}
+		summary, err := apiClient.Ask(message, modelID())
+		if err != nil {
+			return "", "", fmt.Errorf("error summarizing part %d: %w", i+1, err)
+		}
+		parts[i] = fmt.Sprintf("### Part %d\n%s", i+1, strings.TrimSpace(summary))
+	}
+	summaries = strings.Join(parts, "\n\n")
+
+	for prompt.EstimateTokens(summaries)+prompt.EstimateTokens(extra) > budget {
+		condensed, err := condenseSummaries(apiClient, parts, budget)
+		if err != nil {
+			return "", "", err
+		}
+		// Stop when the model cannot make the summaries any shorter
+		if prompt.EstimateTokens(strings.Join(condensed, "\n\n")) >= prompt.EstimateTokens(summaries) {
+			break
+		}
+		parts = condensed
+		summaries = strings.Join(parts, "\n\n")
+	}
+	return "", summaries, nil
+}
+
+// condenseSummaries groups the summaries into chunks that fit the budget and summarizes each chunk again.
+func condenseSummaries(apiClient *skydeck.APIClient, summaries []string, budget int) ([]string, error) {
+	chunks := prompt.Chunk(summaries, budget)
+
+	parts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		fmt.Fprintf(os.Stderr, "Summaries exceed the token budget, condensing part %d of %d...\n", i+1, len(chunks))
+
+		message, err := prompt.Condense(prompt.SummaryData{Part: i + 1, Parts: len(chunks), Diff: chunk})
+		if err != nil {
+			return nil, fmt.Errorf("error rendering condense prompt: %w", err)
+		}
+		summary, err := apiClient.Ask(message, modelID())
+		if err != nil {
+			return nil, fmt.Errorf("error condensing part %d: %w", i+1, err)
+		}
+		parts[i] = fmt.Sprintf("### Part %d\n%s", i+1, strings.TrimSpace(summary))
+	}
+	return parts, nil
+}
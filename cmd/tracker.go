@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/config"
+	"github.com/nlgtEA/lazyai/git"
+	"github.com/nlgtEA/lazyai/github"
+	"github.com/nlgtEA/lazyai/pivotal"
+	"github.com/nlgtEA/lazyai/tracker"
+	"github.com/spf13/viper"
+)
+
+var trackerNames = []string{"pivotal", "jira", "github"}
+
+// trackerName returns the tracker stories are picked from.
+func trackerName() string {
+	return config.TrackerName(viper.GetString)
+}
+
+// pivotalProjectIDs returns the projects stories are listed from: pivotalTracker.projectIDs,
+// or else pivotalTracker.projectID, or else every project of the user.
+func pivotalProjectIDs(ctx context.Context) ([]int, error) {
+	values := settingList("pivotalTracker.projectIDs")
+	if len(values) == 0 {
+		if id := viper.GetInt("pivotalTracker.projectID"); id != 0 {
+			return []int{id}, nil
+		}
+		me, err := pivotalMe(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return me.ProjectIDs(), nil
+	}
+
+	ids := make([]int, len(values))
+	for i, value := range values {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Pivotal Tracker project ID %q", value)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// newTracker returns a client of the configured tracker.
+func newTracker(ctx context.Context) (tracker.Tracker, error) {
+	switch name := trackerName(); name {
+	case "pivotal":
+		projectIDs, err := pivotalProjectIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		owner, err := pivotalOwner(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pt := tracker.NewPivotal(pivotalClient(), projectIDs, owner)
+		if states := settingList("pivotalTracker.states"); len(states) > 0 {
+			pt.Filter.States = states
+		}
+		pt.Filter.Types = settingList("pivotalTracker.types")
+		pt.Filter.Labels = settingList("pivotalTracker.labels")
+		pt.Filter.Query = viper.GetString("pivotalTracker.filter")
+		pt.Iteration = viper.GetString("pivotalTracker.iteration")
+		if pt.Iteration != "" && !slices.Contains([]string{pivotal.CurrentIteration, pivotal.Backlog, pivotal.Icebox}, pt.Iteration) {
+			return nil, fmt.Errorf("unknown iteration %q, expected current, backlog or icebox", pt.Iteration)
+		}
+		return pt, nil
+	case "jira":
+		jira := tracker.NewJira(viper.GetString("jira.baseURL"), viper.GetString("jira.email"), viper.GetString("jira.apiToken"))
+		if jql := viper.GetString("jira.jql"); jql != "" {
+			jira.JQL = jql
+		}
+		return jira, nil
+	case "github":
+		remoteURL, err := git.RemoteURL("origin")
+		if err != nil {
+			return nil, err
+		}
+		owner, repo, err := github.ParseRemote(remoteURL)
+		if err != nil {
+			return nil, err
+		}
+		client := github.NewClient(viper.GetString("github.apiURL"), githubToken())
+		return tracker.NewGitHub(client, owner, repo, viper.GetString("github.user")), nil
+	default:
+		return nil, fmt.Errorf("unknown tracker %q, expected one of: %s", name, strings.Join(trackerNames, ", "))
+	}
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the configuration file in the home directory.
+const FileName = ".lazyai.yml"
+
+// DefaultPath returns the path of the configuration file in the home directory.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error finding home directory: %w", err)
+	}
+	return filepath.Join(home, FileName), nil
+}
+
+// Document is a YAML configuration file that is edited in place, keeping
+// the comments, order and case of the keys it already contains.
+type Document struct {
+	path string
+	root *yaml.Node
+}
+
+// LoadDocument reads the configuration file at path. A missing file results in an empty document.
+func LoadDocument(path string) (*Document, error) {
+	doc := &Document{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	if len(root.Content) == 0 {
+		doc.root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	} else if root.Content[0].Kind == yaml.MappingNode {
+		doc.root = root.Content[0]
+	} else {
+		return nil, fmt.Errorf("error parsing config file %s: the top level must be a mapping", path)
+	}
+
+	return doc, nil
+}
+
+// Path returns the location of the document on disk.
+func (d *Document) Path() string {
+	return d.path
+}
+
+// Settings returns the whole document as nested maps.
+func (d *Document) Settings() (map[string]any, error) {
+	settings := map[string]any{}
+	if err := d.root.Decode(&settings); err != nil {
+		return nil, fmt.Errorf("error decoding config file %s: %w", d.path, err)
+	}
+	return settings, nil
+}
+
+// Get returns the value of a dotted key, such as "skydeck.accessToken". Keys are matched case-insensitively.
+func (d *Document) Get(key string) (any, bool) {
+	node := d.lookup(strings.Split(key, "."), false)
+	if node == nil {
+		return nil, false
+	}
+
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set assigns a value to a dotted key, creating the intermediate mappings when needed.
+func (d *Document) Set(key string, value any) error {
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return fmt.Errorf("error encoding value of %s: %w", key, err)
+	}
+
+	parts := strings.Split(key, ".")
+	parent := d.lookup(parts[:len(parts)-1], true)
+	if parent == nil {
+		return fmt.Errorf("cannot set %s: %s is not a mapping", key, strings.Join(parts[:len(parts)-1], "."))
+	}
+
+	if existing := findKey(parent, parts[len(parts)-1]); existing >= 0 {
+		// Keep the comments attached to the old value
+		valueNode.HeadComment = parent.Content[existing+1].HeadComment
+		valueNode.LineComment = parent.Content[existing+1].LineComment
+		parent.Content[existing+1] = &valueNode
+		return nil
+	}
+
+	parent.Content = append(parent.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: parts[len(parts)-1]},
+		&valueNode,
+	)
+	return nil
+}
+
+// Unset removes a dotted key. It reports whether the key existed.
+func (d *Document) Unset(key string) bool {
+	parts := strings.Split(key, ".")
+	parent := d.lookup(parts[:len(parts)-1], false)
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return false
+	}
+
+	i := findKey(parent, parts[len(parts)-1])
+	if i < 0 {
+		return false
+	}
+	parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+	return true
+}
+
+// Save writes the document back to its file.
+func (d *Document) Save() error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(d.root); err != nil {
+		return fmt.Errorf("error encoding config file: %w", err)
+	}
+	encoder.Close()
+
+	// The file holds API tokens, keep it private
+	return os.WriteFile(d.path, buf.Bytes(), 0o600)
+}
+
+// lookup walks the mappings along parts. When create is set, missing mappings are added.
+func (d *Document) lookup(parts []string, create bool) *yaml.Node {
+	node := d.root
+	for _, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		i := findKey(node, part)
+		if i < 0 {
+			if !create {
+				return nil
+			}
+			child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, child)
+			node = child
+			continue
+		}
+
+		child := node.Content[i+1]
+		if create && child.Kind == yaml.ScalarNode && child.Tag == "!!null" {
+			// An empty section, e.g. "skydeck:" without children
+			child.Kind, child.Tag, child.Value = yaml.MappingNode, "!!map", ""
+		}
+		node = child
+	}
+	return node
+}
+
+// findKey returns the index of the key node in a mapping, or -1.
+func findKey(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Set loads the configuration file at path, assigns a value to a dotted key and saves it.
+func Set(path, key string, value any) error {
+	doc, err := LoadDocument(path)
+	if err != nil {
+		return err
+	}
+	if err := doc.Set(key, value); err != nil {
+		return err
+	}
+	return doc.Save()
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RepoPath returns the path of the shared configuration file at the root of a repository.
+func RepoPath(root string) string {
+	return filepath.Join(root, FileName)
+}
+
+// IsUserOnly reports whether a setting may not be shared through a repository's configuration file.
+func IsUserOnly(name string) bool {
+	if key, ok := Lookup(name); ok && key.Secret {
+		return true
+	}
+	for _, key := range UserOnlyKeys {
+		if strings.EqualFold(name, key) || strings.HasPrefix(strings.ToLower(name), strings.ToLower(key)+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadRepoSettings reads the shared configuration file at path without the
+// secrets and per-user settings it may contain, whose names are returned as
+// dropped. A missing file results in no settings.
+func LoadRepoSettings(path string) (settings map[string]any, dropped []string, err error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+
+	doc, err := LoadDocument(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings, err = doc.Settings()
+	if err != nil {
+		return nil, nil, err
+	}
+	// Filter the decoded settings rather than the document, so that keys spelled
+	// in another case, which viper treats as the same setting, are dropped too
+	return settings, dropUserOnly(settings, ""), nil
+}
+
+// dropUserOnly removes the settings that may not be shared from a nested map and returns their names.
+func dropUserOnly(settings map[string]any, prefix string) []string {
+	var dropped []string
+	for name, value := range settings {
+		if IsUserOnly(prefix + name) {
+			delete(settings, name)
+			dropped = append(dropped, prefix+name)
+			continue
+		}
+		if section, ok := value.(map[string]any); ok {
+			dropped = append(dropped, dropUserOnly(section, prefix+name+".")...)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
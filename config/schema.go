@@ -0,0 +1,339 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of value a setting holds.
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Bool
+	// List is a YAML sequence, or comma-separated values on the command line and in the environment
+	List
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Int:
+		return "integer"
+	case Bool:
+		return "boolean"
+	case List:
+		return "list"
+	default:
+		return "string"
+	}
+}
+
+// Key describes a known setting.
+type Key struct {
+	Name        string
+	Kind        Kind
+	Secret      bool
+	Description string
+	// Min is the smallest value of an integer setting, when not 0
+	Min int
+}
+
+// MinTokenBudget is the smallest skydeck.maxTokens leaving room for a diff next to the instructions of a prompt.
+const MinTokenBudget = 2000
+
+// ProfilesKey is the section holding the profiles, each of which may override the other keys.
+const ProfilesKey = "profiles"
+
+// Keys lists every setting lazyai understands.
+var Keys = []Key{
+	{Name: "profile", Kind: String, Description: "Profile used when no --profile flag or LAZYAI_PROFILE is given"},
+	{Name: "skydeck.accessToken", Kind: String, Secret: true, Description: "SkyDeck access token"},
+	{Name: "skydeck.refreshToken", Kind: String, Secret: true, Description: "SkyDeck refresh token"},
+	{Name: "skydeck.modelID", Kind: Int, Description: "SkyDeck model messages are sent to"},
+	{Name: "skydeck.maxTokens", Kind: Int, Description: "Token budget of a single message", Min: MinTokenBudget},
+	{Name: "skydeck.scope", Kind: String, Description: "Default conversation scope: global, repo or branch"},
+	{Name: "skydeck.convoID", Kind: Int, Description: "Deprecated, conversations are bound to scopes"},
+	{Name: "pivotalTracker.apiToken", Kind: String, Secret: true, Description: "Pivotal Tracker API token"},
+	{Name: "pivotalTracker.projectID", Kind: Int, Description: "Pivotal Tracker project ID, defaults to all your projects"},
+	{Name: "pivotalTracker.owner", Kind: String, Description: "Pivotal Tracker account name stories are filtered by, defaults to yours"},
+	{Name: "pivotalTracker.projectIDs", Kind: List, Description: "Pivotal Tracker projects pickPT lists stories of, instead of projectID"},
+	{Name: "pivotalTracker.states", Kind: List, Description: "States of the stories pickPT lists, default started"},
+	{Name: "pivotalTracker.types", Kind: List, Description: "Types of the stories pickPT lists: feature, bug, chore, release"},
+	{Name: "pivotalTracker.labels", Kind: List, Description: "Labels of the stories pickPT lists, any of them"},
+	{Name: "pivotalTracker.iteration", Kind: String, Description: "Iteration of the stories pickPT lists: current, backlog or icebox"},
+	{Name: "pivotalTracker.filter", Kind: String, Description: "Tracker search query the stories pickPT lists must also match"},
+	{Name: "pivotalTracker.branchTemplate", Kind: String, Description: "Template of the branch names \"lazyai pt branch\" creates"},
+	{Name: "tracker", Kind: String, Description: "Tracker stories are picked from: pivotal, jira or github"},
+	{Name: "jira.baseURL", Kind: String, Description: "Jira site URL, e.g. https://acme.atlassian.net"},
+	{Name: "jira.email", Kind: String, Description: "Jira Cloud account email, leave empty for Jira Server"},
+	{Name: "jira.apiToken", Kind: String, Secret: true, Description: "Jira Cloud API token or Jira Server personal access token"},
+	{Name: "jira.jql", Kind: String, Description: "JQL query selecting your active issues"},
+	{Name: "github.token", Kind: String, Secret: true, Description: "GitHub personal access token"},
+	{Name: "github.user", Kind: String, Description: "GitHub login issues are filtered by, defaults to the owner of the token"},
+	{Name: "github.apiURL", Kind: String, Description: "GitHub REST API base URL"},
+	{Name: "prompts.code", Kind: String, Description: "Custom template replacing the built-in code prompt"},
+	{Name: "prompts.commit", Kind: String, Description: "Custom template replacing the built-in commit prompt"},
+	{Name: "prompts.pr", Kind: String, Description: "Custom template replacing the built-in pr prompt"},
+}
+
+// Required lists the settings each command cannot work without. Commands
+// picking stories also require the settings of the configured tracker.
+var Required = map[string][]string{
+	"sdchat": {"skydeck.accessToken", "skydeck.refreshToken"},
+	"commit": {"skydeck.accessToken", "skydeck.refreshToken"},
+	"pr":     {"skydeck.accessToken", "skydeck.refreshToken"},
+	"pickPT": {},
+	"pt":     {"pivotalTracker.apiToken"},
+	// Commands asking the AI about Tracker stories
+	"pt create": {"pivotalTracker.apiToken", "skydeck.accessToken", "skydeck.refreshToken"},
+	"plan":      {"pivotalTracker.apiToken", "skydeck.accessToken", "skydeck.refreshToken"},
+}
+
+// DefaultTracker is the tracker used when none is configured.
+const DefaultTracker = "pivotal"
+
+// TrackerRequired lists the settings each tracker cannot work without.
+var TrackerRequired = map[string][]string{
+	"pivotal": {"pivotalTracker.apiToken"},
+	"jira":    {"jira.baseURL", "jira.apiToken"},
+	// GitHub needs github.user or a token, which may also come from GITHUB_TOKEN
+	"github": {},
+}
+
+// TrackerCommands lists the commands that pick stories from the configured tracker.
+var TrackerCommands = []string{"pickPT"}
+
+// UserOnlyKeys are per-user settings that are ignored in a repository's configuration file, besides secrets.
+// Endpoints receiving a token belong here, or a cloned repository could send the token to any host.
+var UserOnlyKeys = []string{"profile", ProfilesKey, "github.apiURL", "jira.baseURL"}
+
+// Lookup finds a known setting by name, case-insensitively. Settings inside
+// a profile, such as profiles.work.skydeck.accessToken, resolve to the setting they override.
+func Lookup(name string) (Key, bool) {
+	if rest, ok := profileSetting(name); ok {
+		if strings.EqualFold(rest, "profile") {
+			return Key{}, false
+		}
+		name = rest
+	}
+
+	for _, key := range Keys {
+		if strings.EqualFold(key.Name, name) {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// profileSetting returns the setting name of a key inside a profile, e.g. skydeck.modelID for profiles.work.skydeck.modelID.
+func profileSetting(name string) (string, bool) {
+	parts := strings.SplitN(name, ".", 3)
+	if len(parts) != 3 || !strings.EqualFold(parts[0], ProfilesKey) {
+		return "", false
+	}
+	return parts[2], true
+}
+
+// Parse converts the textual value of a setting to its kind.
+func (k Key) Parse(value string) (any, error) {
+	switch k.Kind {
+	case Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer, got %q", k.Name, value)
+		}
+		if k.Min != 0 && n < k.Min {
+			return nil, fmt.Errorf("%s must be at least %d, got %d", k.Name, k.Min, n)
+		}
+		return n, nil
+	case Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, got %q", k.Name, value)
+		}
+		return b, nil
+	case List:
+		return SplitList(value), nil
+	default:
+		return value, nil
+	}
+}
+
+// SplitList splits comma-separated values, dropping empty ones.
+func SplitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// check reports whether a decoded YAML value fits the kind of the setting.
+func (k Key) check(value any) bool {
+	switch k.Kind {
+	case Int:
+		switch v := value.(type) {
+		case int:
+			return true
+		case string:
+			_, err := strconv.Atoi(v)
+			return err == nil
+		}
+		return false
+	case Bool:
+		_, ok := value.(bool)
+		return ok
+	case List:
+		items, ok := value.([]any)
+		if !ok {
+			return Key{Kind: String}.check(value)
+		}
+		for _, item := range items {
+			if !(Key{Kind: String}).check(item) {
+				return false
+			}
+		}
+		return true
+	default:
+		switch value.(type) {
+		case string, int, float64, bool:
+			return true
+		}
+		return false
+	}
+}
+
+// Validate reports unknown keys and values of the wrong type in the settings of a configuration file.
+func Validate(settings map[string]any) []string {
+	var problems []string
+	for _, name := range flatten("", settings) {
+		value := lookupValue(settings, name)
+
+		if value == nil {
+			continue
+		}
+		if m, ok := value.(map[string]any); ok && len(m) == 0 {
+			continue
+		}
+
+		key, ok := Lookup(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown key %s%s", name, suggest(name)))
+			continue
+		}
+		if !key.check(value) {
+			problems = append(problems, fmt.Sprintf("%s has the wrong type, expected %s, got %v", name, key.Kind, value))
+			continue
+		}
+		if n, err := strconv.Atoi(fmt.Sprint(value)); key.Min != 0 && err == nil && n < key.Min {
+			problems = append(problems, fmt.Sprintf("%s must be at least %d, got %d", name, key.Min, n))
+		}
+	}
+	return problems
+}
+
+// Missing returns the settings required by command that get returns no value for.
+func Missing(command string, get func(key string) string) []string {
+	names := Required[command]
+	if slices.Contains(TrackerCommands, command) {
+		names = append(slices.Clone(names), TrackerRequired[TrackerName(get)]...)
+	}
+
+	var missing []string
+	for _, name := range names {
+		if get(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// TrackerName returns the configured tracker.
+func TrackerName(get func(key string) string) string {
+	if name := get("tracker"); name != "" {
+		return name
+	}
+	return DefaultTracker
+}
+
+// flatten returns the dotted names of the leaves of a nested map, sorted.
+func flatten(prefix string, settings map[string]any) []string {
+	var names []string
+	for name, value := range settings {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		child, ok := value.(map[string]any)
+		_, known := Lookup(name)
+		if ok && len(child) > 0 && !known {
+			names = append(names, flatten(name, child)...)
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupValue(settings map[string]any, name string) any {
+	var value any = settings
+	for _, part := range strings.Split(name, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[part]
+	}
+	return value
+}
+
+// suggest returns a hint naming the known setting closest to an unknown name, if any is close enough.
+func suggest(name string) string {
+	setting, inProfile := profileSetting(name)
+	if !inProfile {
+		setting = name
+	}
+
+	best, bestDistance := "", 4
+	for _, key := range Keys {
+		if d := distance(strings.ToLower(setting), strings.ToLower(key.Name)); d < bestDistance {
+			best, bestDistance = key.Name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	if inProfile {
+		best = name[:len(name)-len(setting)] + best
+	}
+	return fmt.Sprintf(", did you mean %s?", best)
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
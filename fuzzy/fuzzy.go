@@ -0,0 +1,63 @@
+// Package fuzzy matches text against patterns typed by users.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nlgtEA/lazyai/tracker"
+)
+
+// Score reports whether the characters of pattern appear in text in order, ignoring case,
+// and scores the match: consecutive characters and characters starting a word score higher.
+func Score(pattern, text string) (int, bool) {
+	pattern = strings.ToLower(strings.Join(strings.Fields(pattern), ""))
+	if pattern == "" {
+		return 0, true
+	}
+
+	target := []rune(strings.ToLower(text))
+	score, p, prev := 0, []rune(pattern), -2
+	for i := 0; i < len(target) && len(p) > 0; i++ {
+		if target[i] != p[0] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(target[i-1]) && !unicode.IsDigit(target[i-1]) {
+			score += 3
+		}
+		prev, p = i, p[1:]
+	}
+	return score, len(p) == 0
+}
+
+// Filter returns the items whose text matches pattern, best matches first.
+func Filter[T any](items []T, pattern string, text func(T) string) []T {
+	type match struct {
+		item  T
+		score int
+	}
+
+	var matches []match
+	for _, item := range items {
+		if score, ok := Score(pattern, text(item)); ok {
+			matches = append(matches, match{item, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	filtered := make([]T, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.item
+	}
+	return filtered
+}
+
+// IssueText returns the text issues are matched on: their ID, title and labels.
+func IssueText(issue tracker.Issue) string {
+	return issue.ID + " " + issue.Title + " " + strings.Join(issue.Labels, " ")
+}
@@ -0,0 +1,171 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DiffOptions selects which changes are collected.
+type DiffOptions struct {
+	// Base is the revision, or revision range, to compare against. When empty,
+	// the working tree is compared against the index.
+	Base string
+	// Staged compares the index against Base instead of the working tree.
+	Staged bool
+	// Ignore filters out files that should not be sent to the model.
+	Ignore *IgnoreRules
+}
+
+// FileDiff is the patch of a single file.
+type FileDiff struct {
+	Path    string
+	Added   int
+	Deleted int
+	Patch   string
+}
+
+// FileStat is the number of lines changed in a file, as listed by git diff --numstat.
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// ExcludedFile is a changed file left out of the diff, and why.
+type ExcludedFile struct {
+	FileStat
+	Reason string
+}
+
+// Diff is a set of changes ready to be put in a prompt.
+type Diff struct {
+	Files    []FileDiff
+	Excluded []ExcludedFile
+}
+
+// StagedDiff returns the options for the changes staged for the next commit.
+func StagedDiff() DiffOptions {
+	return DiffOptions{Base: headOrEmptyTree(), Staged: true}
+}
+
+// UnstagedDiff returns the options for the working tree changes that are not staged yet.
+func UnstagedDiff() DiffOptions {
+	return DiffOptions{}
+}
+
+// WorkingTreeDiff returns the options for all changes to tracked files since the last commit.
+func WorkingTreeDiff() DiffOptions {
+	return DiffOptions{Base: headOrEmptyTree()}
+}
+
+func headOrEmptyTree() string {
+	if HasCommit("HEAD") {
+		return "HEAD"
+	}
+	return EmptyTree
+}
+
+// CollectDiff gathers the changes described by opts, skipping binary and ignored files.
+func CollectDiff(opts DiffOptions) (*Diff, error) {
+	if opts.Ignore == nil {
+		root, err := RepoRoot()
+		if err != nil {
+			return nil, err
+		}
+		if opts.Ignore, err = LoadIgnoreRules(root); err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", IgnoreFile, err)
+		}
+	}
+
+	args := []string{"--no-renames", "--no-relative"}
+	if opts.Staged {
+		args = append(args, "--cached")
+	}
+	if opts.Base != "" {
+		args = append(args, opts.Base)
+	}
+
+	stats, err := Run(append([]string{"diff", "--numstat", "-z"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &Diff{}
+	for _, stat := range parseNumstat(stats) {
+		if stat.Binary {
+			diff.Excluded = append(diff.Excluded, ExcludedFile{stat, "binary"})
+			continue
+		}
+		if ignored, by := opts.Ignore.Match(stat.Path); ignored {
+			diff.Excluded = append(diff.Excluded, ExcludedFile{stat, fmt.Sprintf("ignored by %q", by)})
+			continue
+		}
+
+		patch, err := runDiff(append(args, "--", ":(top,literal)"+stat.Path)...)
+		if err != nil {
+			return nil, err
+		}
+		diff.Files = append(diff.Files, FileDiff{
+			Path:    stat.Path,
+			Added:   stat.Added,
+			Deleted: stat.Deleted,
+			Patch:   patch,
+		})
+	}
+
+	return diff, nil
+}
+
+func parseNumstat(out string) []FileStat {
+	var stats []FileStat
+	for _, record := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		stat := FileStat{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			stat.Binary = true
+		} else {
+			stat.Added, _ = strconv.Atoi(fields[0])
+			stat.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// IsEmpty reports whether no file changed at all.
+func (d *Diff) IsEmpty() bool {
+	return len(d.Files) == 0 && len(d.Excluded) == 0
+}
+
+// Patch returns the concatenated patches of the included files.
+func (d *Diff) Patch() string {
+	patches := make([]string, len(d.Files))
+	for i, file := range d.Files {
+		patches[i] = file.Patch
+	}
+	return strings.Join(patches, "\n")
+}
+
+// ExcludedSummary describes the files left out of the patch, one per line.
+func (d *Diff) ExcludedSummary() string {
+	if len(d.Excluded) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("The following files also changed but their content is not shown:\n")
+	for _, file := range d.Excluded {
+		if file.Binary {
+			fmt.Fprintf(&b, "- %s (%s)\n", file.Path, file.Reason)
+		} else {
+			fmt.Fprintf(&b, "- %s (%s, +%d -%d)\n", file.Path, file.Reason, file.Added, file.Deleted)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,132 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// EmptyTree is the hash of git's empty tree, used to diff against when there is no parent commit.
+const EmptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// Run executes git with the given arguments and returns its trimmed standard output.
+func Run(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// RepoRoot returns the top-level directory of the current repository.
+func RepoRoot() (string, error) {
+	return Run("rev-parse", "--show-toplevel")
+}
+
+// HasCommit reports whether the given revision resolves to a commit.
+func HasCommit(rev string) bool {
+	_, err := Run("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	return err == nil
+}
+
+// runDiff returns the output of git diff with the given arguments.
+func runDiff(args ...string) (string, error) {
+	return Run(append([]string{"diff", "--no-color", "--no-ext-diff"}, args...)...)
+}
+
+// CurrentBranch returns the name of the checked out branch.
+func CurrentBranch() (string, error) {
+	return Run("symbolic-ref", "--short", "HEAD")
+}
+
+// BranchExists reports whether a local branch exists.
+func BranchExists(name string) bool {
+	_, err := Run("rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// Checkout switches to a branch, creating it from HEAD first when create is set.
+func Checkout(name string, create bool) error {
+	args := []string{"checkout", name}
+	if create {
+		args = []string{"checkout", "-b", name}
+	}
+	_, err := Run(args...)
+	return err
+}
+
+// AddWorktree checks out a branch in a new worktree at path, creating the branch from HEAD first when create is set.
+func AddWorktree(path, branch string, create bool) error {
+	args := []string{"worktree", "add", path, branch}
+	if create {
+		args = []string{"worktree", "add", "-b", branch, path}
+	}
+	_, err := Run(args...)
+	return err
+}
+
+// GitPath returns the path of a file inside the repository's git directory, e.g. .git/<name>.
+// Worktrees get their own path.
+func GitPath(name string) (string, error) {
+	return Run("rev-parse", "--git-path", name)
+}
+
+// Upstream returns the remote-tracking branch the current branch is set to track, e.g. origin/feature.
+func Upstream() (string, error) {
+	return Run("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+}
+
+// CountCommits returns the number of commits in a revision range.
+func CountCommits(revRange string) (int, error) {
+	out, err := Run("rev-list", "--count", revRange)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(out)
+}
+
+// RemoteURL returns the fetch URL of the named remote.
+func RemoteURL(remote string) (string, error) {
+	return Run("remote", "get-url", remote)
+}
+
+// DefaultBranch returns the branch the remote's HEAD points to, falling back to "main".
+func DefaultBranch(remote string) string {
+	ref, err := Run("symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return "main"
+	}
+	return strings.TrimPrefix(ref, remote+"/")
+}
+
+// Log returns the abbreviated hash, subject and body of the commits in the given revision range.
+func Log(revRange string) (string, error) {
+	return Run("log", "--no-color", "--format=%h %s%n%n%b", revRange)
+}
+
+// Messages returns the full messages of at most n commits of a revision range, newest first.
+func Messages(revRange string, n int) ([]string, error) {
+	out, err := Run("log", "--no-color", "--format=%B%x00", fmt.Sprintf("--max-count=%d", n), revRange)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for _, message := range strings.Split(out, "\x00") {
+		if message = strings.TrimSpace(message); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,143 @@
+package git
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFile is the name of the pattern file, at the repository root, listing files kept out of prompts.
+const IgnoreFile = ".lazyaiignore"
+
+// DefaultIgnorePatterns lists generated files that rarely help the model understand a change.
+var DefaultIgnorePatterns = []string{
+	"go.sum",
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"Cargo.lock",
+	"Gemfile.lock",
+	"poetry.lock",
+	"composer.lock",
+	"*.min.js",
+	"*.min.css",
+	"vendor/",
+	"node_modules/",
+}
+
+type ignoreRule struct {
+	pattern string
+	negate  bool
+	re      *regexp.Regexp
+}
+
+// IgnoreRules matches paths against gitignore-style patterns.
+type IgnoreRules struct {
+	rules []ignoreRule
+}
+
+// NewIgnoreRules compiles the given gitignore-style patterns. Later patterns take precedence.
+func NewIgnoreRules(patterns ...string) *IgnoreRules {
+	ignore := &IgnoreRules{}
+	for _, pattern := range patterns {
+		ignore.Add(pattern)
+	}
+	return ignore
+}
+
+// LoadIgnoreRules returns the default rules extended with the .lazyaiignore file of the repository, if any.
+func LoadIgnoreRules(root string) (*IgnoreRules, error) {
+	ignore := NewIgnoreRules(DefaultIgnorePatterns...)
+
+	file, err := os.Open(filepath.Join(root, IgnoreFile))
+	if os.IsNotExist(err) {
+		return ignore, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		ignore.Add(scanner.Text())
+	}
+	return ignore, scanner.Err()
+}
+
+// Add appends a pattern. Blank lines and lines starting with '#' are skipped.
+func (ig *IgnoreRules) Add(pattern string) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return
+	}
+
+	rule := ignoreRule{pattern: pattern}
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	}
+	rule.re = compilePattern(pattern)
+	ig.rules = append(ig.rules, rule)
+}
+
+// Match reports whether the slash-separated path, relative to the repository root, is ignored.
+func (ig *IgnoreRules) Match(path string) (bool, string) {
+	ignored, by := false, ""
+	for _, rule := range ig.rules {
+		if rule.re.MatchString(path) {
+			ignored, by = !rule.negate, rule.pattern
+		}
+	}
+	return ignored, by
+}
+
+// compilePattern turns a gitignore-style pattern into a regular expression matching a file path.
+func compilePattern(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	// Patterns containing a slash other than at the end are relative to the root
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("(?:^|/)")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					expr.WriteString("(?:.*/)?")
+				} else {
+					expr.WriteString(".*")
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	// A pattern matching a directory also matches everything below it
+	if dirOnly {
+		expr.WriteString("/")
+	} else {
+		expr.WriteString("(?:/|$)")
+	}
+
+	return regexp.MustCompile(expr.String())
+}
@@ -0,0 +1,137 @@
+package git
+
+import "testing"
+
+func TestIgnoreRulesMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		ignored  bool
+		by       string
+	}{
+		{
+			name:     "name at any depth",
+			patterns: []string{"*.log"},
+			path:     "logs/app.log",
+			ignored:  true,
+			by:       "*.log",
+		},
+		{
+			name:     "star does not cross directories",
+			patterns: []string{"logs/*.log"},
+			path:     "logs/old/app.log",
+			ignored:  false,
+		},
+		{
+			name:     "question mark matches one character",
+			patterns: []string{"file?.txt"},
+			path:     "file1.txt",
+			ignored:  true,
+			by:       "file?.txt",
+		},
+		{
+			name:     "leading double star matches at the root",
+			patterns: []string{"**/build"},
+			path:     "build/out.js",
+			ignored:  true,
+			by:       "**/build",
+		},
+		{
+			name:     "leading double star matches in subdirectories",
+			patterns: []string{"**/build"},
+			path:     "web/app/build/out.js",
+			ignored:  true,
+			by:       "**/build",
+		},
+		{
+			name:     "trailing double star matches everything inside",
+			patterns: []string{"docs/**"},
+			path:     "docs/api/index.md",
+			ignored:  true,
+			by:       "docs/**",
+		},
+		{
+			name:     "middle double star matches no directory",
+			patterns: []string{"a/**/b.go"},
+			path:     "a/b.go",
+			ignored:  true,
+			by:       "a/**/b.go",
+		},
+		{
+			name:     "middle double star matches several directories",
+			patterns: []string{"a/**/b.go"},
+			path:     "a/x/y/b.go",
+			ignored:  true,
+			by:       "a/**/b.go",
+		},
+		{
+			name:     "leading slash anchors to the root",
+			patterns: []string{"/schema.sql"},
+			path:     "schema.sql",
+			ignored:  true,
+			by:       "/schema.sql",
+		},
+		{
+			name:     "leading slash does not match in subdirectories",
+			patterns: []string{"/schema.sql"},
+			path:     "db/schema.sql",
+			ignored:  false,
+		},
+		{
+			name:     "trailing slash matches files inside the directory",
+			patterns: []string{"vendor/"},
+			path:     "lib/vendor/pkg/a.go",
+			ignored:  true,
+			by:       "vendor/",
+		},
+		{
+			name:     "trailing slash does not match a file",
+			patterns: []string{"vendor/"},
+			path:     "vendor",
+			ignored:  false,
+		},
+		{
+			name:     "negation after a match keeps the file",
+			patterns: []string{"*.log", "!keep.log"},
+			path:     "keep.log",
+			ignored:  false,
+			by:       "!keep.log",
+		},
+		{
+			name:     "negation before a match is overridden",
+			patterns: []string{"!keep.log", "*.log"},
+			path:     "keep.log",
+			ignored:  true,
+			by:       "*.log",
+		},
+		{
+			name:     "bracket classes are unsupported and match literally",
+			patterns: []string{"file[0-9].txt"},
+			path:     "file1.txt",
+			ignored:  false,
+		},
+		{
+			name:     "bracket pattern matches the literal name",
+			patterns: []string{"file[0-9].txt"},
+			path:     "file[0-9].txt",
+			ignored:  true,
+			by:       "file[0-9].txt",
+		},
+		{
+			name:     "comments and blank lines are skipped",
+			patterns: []string{"# *.go", "", "  "},
+			path:     "main.go",
+			ignored:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ignored, by := NewIgnoreRules(tt.patterns...).Match(tt.path)
+			if ignored != tt.ignored || by != tt.by {
+				t.Errorf("Match(%q) = %v, %q, want %v, %q", tt.path, ignored, by, tt.ignored, tt.by)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TrackedFiles returns the slash-separated paths, relative to the repository root, of the files tracked by git.
+func TrackedFiles() ([]string, error) {
+	out, err := Run("ls-files", "-z", "--full-name", "--", ":(top)")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, file := range strings.Split(out, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// RepoMap returns an indented tree of the tracked files that are not ignored. When the tree is
+// longer than maxLines, the deepest directories are collapsed into their number of files.
+func RepoMap(ignore *IgnoreRules, maxLines int) (string, error) {
+	files, err := TrackedFiles()
+	if err != nil {
+		return "", err
+	}
+
+	root := &dirNode{}
+	for _, file := range files {
+		if ignored, _ := ignore.Match(file); !ignored {
+			root.add(strings.Split(file, "/"))
+		}
+	}
+
+	var lines []string
+	for depth := root.depth(); depth >= 0; depth-- {
+		lines = root.render(nil, "", depth)
+		if len(lines) <= maxLines {
+			break
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// dirNode is a directory of the repository map.
+type dirNode struct {
+	dirs  map[string]*dirNode
+	files []string
+}
+
+func (d *dirNode) add(parts []string) {
+	if len(parts) == 1 {
+		d.files = append(d.files, parts[0])
+		return
+	}
+	if d.dirs == nil {
+		d.dirs = map[string]*dirNode{}
+	}
+	child, ok := d.dirs[parts[0]]
+	if !ok {
+		child = &dirNode{}
+		d.dirs[parts[0]] = child
+	}
+	child.add(parts[1:])
+}
+
+// depth returns the number of directory levels below d.
+func (d *dirNode) depth() int {
+	depth := 0
+	for _, child := range d.dirs {
+		depth = max(depth, child.depth()+1)
+	}
+	return depth
+}
+
+// count returns the number of files in d and its subdirectories.
+func (d *dirNode) count() int {
+	n := len(d.files)
+	for _, child := range d.dirs {
+		n += child.count()
+	}
+	return n
+}
+
+// render appends the lines of the contents of d to lines, listing the files of at most depth levels of subdirectories.
+func (d *dirNode) render(lines []string, indent string, depth int) []string {
+	names := make([]string, 0, len(d.dirs))
+	for name := range d.dirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child := d.dirs[name]
+		if depth == 0 {
+			lines = append(lines, fmt.Sprintf("%s%s/ (%d files)", indent, name, child.count()))
+			continue
+		}
+		lines = append(lines, indent+name+"/")
+		lines = child.render(lines, indent+"  ", depth-1)
+	}
+
+	sort.Strings(d.files)
+	for _, file := range d.files {
+		lines = append(lines, indent+file)
+	}
+	return lines
+}
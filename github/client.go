@@ -0,0 +1,183 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const DefaultBaseURL = "https://api.github.com"
+
+type Client struct {
+	Client  *http.Client
+	BaseURL string
+	Token   string
+}
+
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		Client:  &http.Client{},
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+	}
+}
+
+type NewPullRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body"`
+	Draft bool   `json:"draft"`
+}
+
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+}
+
+// CreatePullRequest opens a pull request in the owner/repo repository.
+func (c *Client) CreatePullRequest(owner, repo string, pr NewPullRequest) (*PullRequest, error) {
+	var created PullRequest
+	path := fmt.Sprintf("/repos/%s/%s/pulls", owner, repo)
+	if err := c.do(http.MethodPost, path, pr, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+type User struct {
+	Login string `json:"login"`
+}
+
+type Issue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	// PullRequest is set when the issue is a pull request
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// CurrentUser returns the user the token belongs to.
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.doContext(ctx, http.MethodGet, "/user", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListAssignedIssues returns the open issues of the owner/repo repository assigned to assignee, pull requests excluded.
+func (c *Client) ListAssignedIssues(ctx context.Context, owner, repo, assignee string) ([]Issue, error) {
+	query := url.Values{}
+	query.Set("assignee", assignee)
+	query.Set("state", "open")
+	query.Set("per_page", "100")
+
+	var issues []Issue
+	path := fmt.Sprintf("/repos/%s/%s/issues?%s", owner, repo, query.Encode())
+	if err := c.doContext(ctx, http.MethodGet, path, nil, &issues); err != nil {
+		return nil, err
+	}
+
+	filtered := issues[:0]
+	for _, issue := range issues {
+		if issue.PullRequest == nil {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered, nil
+}
+
+// GetIssue returns a single issue of the owner/repo repository.
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+	var issue Issue
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d", owner, repo, number)
+	if err := c.doContext(ctx, http.MethodGet, path, nil, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// SetIssueState opens or closes an issue.
+func (c *Client) SetIssueState(ctx context.Context, owner, repo string, number int, state string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d", owner, repo, number)
+	return c.doContext(ctx, http.MethodPatch, path, map[string]string{"state": state}, nil)
+}
+
+// CreateIssueComment adds a comment to an issue.
+func (c *Client) CreateIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, repo, number)
+	return c.doContext(ctx, http.MethodPost, path, map[string]string{"body": body}, nil)
+}
+
+func (c *Client) do(method, path string, body, out any) error {
+	return c.doContext(context.Background(), method, path, body, out)
+}
+
+func (c *Client) doContext(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("received %d response code from GitHub, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %v", err)
+	}
+	return nil
+}
+
+var remotePattern = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?[^:/]+(?::\d+)?[:/](.+?)/([^/]+?)(?:\.git)?/?$`)
+
+// ParseRemote extracts the owner and repository name from an SSH or HTTPS git remote URL.
+func ParseRemote(remoteURL string) (owner, repo string, err error) {
+	m := remotePattern.FindStringSubmatch(strings.TrimSpace(remoteURL))
+	if m == nil {
+		return "", "", fmt.Errorf("cannot parse GitHub repository from remote %q", remoteURL)
+	}
+	return m[1], m[2], nil
+}
@@ -0,0 +1,319 @@
+// Package picker is a full screen list of issues with fuzzy filtering and a preview of the highlighted issue.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	glamouransi "github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/nlgtEA/lazyai/fuzzy"
+	"github.com/nlgtEA/lazyai/tracker"
+)
+
+// ErrCanceled is returned when the user leaves the picker without picking an issue.
+var ErrCanceled = errors.New("no story picked")
+
+// Action is run on the highlighted issue when its key is pressed. It returns the issue as it is after
+// the action, which replaces the one in the list, and a message shown in the status line.
+type Action struct {
+	Key  string
+	Help string
+	Run  func(issue tracker.Issue) (tracker.Issue, string, error)
+}
+
+var (
+	cursorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true)
+	titleStyle   = lipgloss.NewStyle().Bold(true)
+	dimStyle     = lipgloss.NewStyle().Faint(true)
+	labelStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("10")).Padding(0, 1)
+	previewStyle = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderLeft(true).PaddingLeft(1)
+	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+var (
+	upKey     = key.NewBinding(key.WithKeys("up", "ctrl+p", "ctrl+k"))
+	downKey   = key.NewBinding(key.WithKeys("down", "ctrl+n", "ctrl+j"))
+	pickKey   = key.NewBinding(key.WithKeys("enter"))
+	cancelKey = key.NewBinding(key.WithKeys("esc", "ctrl+c"))
+)
+
+type statusMsg struct {
+	text string
+	err  error
+}
+
+// actionMsg is the result of an action run on the issue at index.
+type actionMsg struct {
+	index int
+	issue tracker.Issue
+	statusMsg
+}
+
+type model struct {
+	issues  []tracker.Issue
+	actions []Action
+	input   textinput.Model
+	// matches are the indexes of the issues matching the filter, best first
+	matches []int
+	cursor  int
+	offset  int
+	width   int
+	height  int
+	status  statusMsg
+	picked  int
+
+	// style renders descriptions, with a renderer and the rendered descriptions kept for the current width
+	style         glamouransi.StyleConfig
+	renderer      *glamour.TermRenderer
+	rendererWidth int
+	rendered      map[string]string
+}
+
+// Run shows the picker and returns the index of the picked issue. Issues changed by actions are updated in issues.
+func Run(issues []tracker.Issue, actions ...Action) (int, error) {
+	m := &model{issues: issues, actions: actions, input: newInput(), picked: -1, width: 80, height: 24}
+	// The background color is queried before the program starts reading the terminal
+	m.style = markdownStyle()
+	m.filter()
+
+	final, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	if err != nil {
+		return 0, err
+	}
+	if picked := final.(*model).picked; picked >= 0 {
+		return picked, nil
+	}
+	return 0, ErrCanceled
+}
+
+func newInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Filter by name, label or ID"
+	input.Focus()
+	return input
+}
+
+func (m *model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+		return m, nil
+
+	case statusMsg:
+		m.status = msg
+		return m, nil
+
+	case actionMsg:
+		if msg.err == nil {
+			m.issues[msg.index] = msg.issue
+		}
+		m.status = msg.statusMsg
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, cancelKey):
+			return m, tea.Quit
+		case key.Matches(msg, pickKey):
+			if len(m.matches) > 0 {
+				m.picked = m.matches[m.cursor]
+				return m, tea.Quit
+			}
+			return m, nil
+		case key.Matches(msg, upKey):
+			m.move(-1)
+			return m, nil
+		case key.Matches(msg, downKey):
+			m.move(1)
+			return m, nil
+		}
+
+		for _, action := range m.actions {
+			if msg.String() == action.Key && len(m.matches) > 0 {
+				index, run := m.matches[m.cursor], action.Run
+				issue := m.issues[index]
+				m.status = statusMsg{text: action.Help + "..."}
+				return m, func() tea.Msg {
+					updated, text, err := run(issue)
+					return actionMsg{index, updated, statusMsg{text, err}}
+				}
+			}
+		}
+	}
+
+	previous := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.filter()
+	}
+	return m, cmd
+}
+
+// filter keeps the issues matching the text typed, best matches first.
+func (m *model) filter() {
+	indexes := make([]int, len(m.issues))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	m.matches = fuzzy.Filter(indexes, m.input.Value(), func(i int) string {
+		return fuzzy.IssueText(m.issues[i])
+	})
+	m.cursor, m.offset = 0, 0
+}
+
+func (m *model) move(delta int) {
+	m.cursor = max(0, min(len(m.matches)-1, m.cursor+delta))
+	m.scroll()
+}
+
+// scroll keeps the cursor in the visible part of the list.
+func (m *model) scroll() {
+	rows := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+// listHeight is the number of issues shown, leaving room for the filter, help and status lines.
+func (m *model) listHeight() int {
+	return max(1, m.height-3)
+}
+
+func (m *model) View() string {
+	listWidth := max(20, m.width*2/5)
+	previewWidth := max(20, m.width-listWidth-3)
+
+	list := []string{m.input.View()}
+	for i := m.offset; i < len(m.matches) && i < m.offset+m.listHeight(); i++ {
+		issue := m.issues[m.matches[i]]
+		title := issue.Title
+		if issue.Project != "" {
+			title = fmt.Sprintf("[%s] %s", issue.Project, title)
+		}
+		line := ansi.Truncate(title, listWidth-2, "…")
+		if i == m.cursor {
+			list = append(list, cursorStyle.Render("› "+line))
+		} else {
+			list = append(list, "  "+line)
+		}
+	}
+	if len(m.matches) == 0 {
+		list = append(list, dimStyle.Render("  No matching stories"))
+	}
+	left := lipgloss.NewStyle().Width(listWidth).Height(m.height - 2).Render(strings.Join(list, "\n"))
+
+	preview := ""
+	if len(m.matches) > 0 {
+		preview = m.preview(m.issues[m.matches[m.cursor]], previewWidth)
+	}
+	lines := strings.Split(preview, "\n")
+	if len(lines) > m.height-2 {
+		lines = lines[:max(0, m.height-2)]
+	}
+	right := previewStyle.Height(m.height - 2).Render(strings.Join(lines, "\n"))
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right) + "\n" + m.footer()
+}
+
+// preview renders the details of an issue.
+func (m *model) preview(issue tracker.Issue, width int) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, titleStyle.Render(ansi.Wrap(issue.Title, width, "")))
+
+	details := []string{"#" + issue.ID}
+	if issue.Type != "" {
+		details = append(details, issue.Type)
+	}
+	if issue.Estimate != nil {
+		details = append(details, strconv.FormatFloat(*issue.Estimate, 'f', -1, 64)+" points")
+	}
+	if issue.State != "" {
+		details = append(details, issue.State)
+	}
+	fmt.Fprintln(&b, dimStyle.Render(strings.Join(details, " · ")))
+
+	if len(issue.Labels) > 0 {
+		labels := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			labels[i] = labelStyle.Render(label)
+		}
+		fmt.Fprintln(&b, strings.Join(labels, " "))
+	}
+	if issue.Project != "" {
+		fmt.Fprintln(&b, "Project: "+issue.Project)
+	}
+	fmt.Fprintln(&b, dimStyle.Render(ansi.Truncate(issue.URL, width, "…")))
+
+	if description := strings.TrimSpace(issue.Description); description != "" {
+		fmt.Fprintln(&b)
+		fmt.Fprint(&b, m.render(description, width))
+	}
+	return b.String()
+}
+
+// markdownStyle returns the style descriptions are rendered with, matching the background of the terminal.
+func markdownStyle() glamouransi.StyleConfig {
+	style := glamour.DarkStyleConfig
+	if !lipgloss.HasDarkBackground() {
+		style = glamour.LightStyleConfig
+	}
+	// The preview pane has its own padding
+	margin := uint(0)
+	style.Document.Margin = &margin
+	style.Document.BlockPrefix, style.Document.BlockSuffix = "", ""
+	return style
+}
+
+// render renders a Markdown description wrapped at width, falling back to the wrapped text.
+func (m *model) render(description string, width int) string {
+	if m.renderer == nil || m.rendererWidth != width {
+		renderer, err := glamour.NewTermRenderer(glamour.WithStyles(m.style), glamour.WithWordWrap(width))
+		if err != nil {
+			return ansi.Wrap(description, width, "")
+		}
+		m.renderer, m.rendererWidth, m.rendered = renderer, width, map[string]string{}
+	}
+
+	if rendered, ok := m.rendered[description]; ok {
+		return rendered
+	}
+	rendered, err := m.renderer.Render(description)
+	if err != nil {
+		return ansi.Wrap(description, width, "")
+	}
+	rendered = strings.Trim(rendered, "\n")
+	m.rendered[description] = rendered
+	return rendered
+}
+
+func (m *model) footer() string {
+	help := []string{"enter pick", "esc cancel"}
+	for _, action := range m.actions {
+		help = append(help, action.Key+" "+action.Help)
+	}
+	footer := dimStyle.Render(strings.Join(help, " · "))
+
+	if m.status.err != nil {
+		footer += "  " + errorStyle.Render(m.status.err.Error())
+	} else if m.status.text != "" {
+		footer += "  " + m.status.text
+	}
+	return ansi.Truncate(footer, m.width, "…")
+}
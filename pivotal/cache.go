@@ -0,0 +1,48 @@
+package pivotal
+
+import (
+	"net/http"
+	"time"
+)
+
+// ResponseCache stores the responses of GET requests, so that they can be revalidated
+// with ETag or Last-Modified and served when Tracker cannot be reached.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Put(key string, response *CachedResponse)
+}
+
+type CachedResponse struct {
+	Header    map[string]string `json:"header"`
+	Body      []byte            `json:"body"`
+	FetchedAt time.Time         `json:"fetchedAt"`
+}
+
+// cachedHeaders are the response headers kept with cached responses.
+var cachedHeaders = []string{
+	"ETag",
+	"Last-Modified",
+	"X-Tracker-Pagination-Total",
+	"X-Tracker-Pagination-Offset",
+	"X-Tracker-Pagination-Limit",
+	"X-Tracker-Pagination-Returned",
+}
+
+func newCachedResponse(resp *http.Response, body []byte) *CachedResponse {
+	cached := &CachedResponse{Header: map[string]string{}, Body: body, FetchedAt: time.Now()}
+	for _, name := range cachedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			cached.Header[name] = value
+		}
+	}
+	return cached
+}
+
+// response rebuilds the response a cached one was made from.
+func (c *CachedResponse) response() *http.Response {
+	header := http.Header{}
+	for name, value := range c.Header {
+		header.Set(name, value)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: header}
+}
@@ -0,0 +1,200 @@
+// Package pivotal is a client of the Pivotal Tracker REST API v5.
+package pivotal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://www.pivotaltracker.com/services/v5"
+
+// pageSize is the number of items requested per page, the maximum Tracker allows.
+const pageSize = 500
+
+type Client struct {
+	Client  *http.Client
+	BaseURL string
+	Token   string
+	// Cache, when set, keeps GET responses to revalidate them and to serve them when Tracker cannot be reached
+	Cache ResponseCache
+	// Refresh fetches every response again instead of revalidating the cached ones
+	Refresh bool
+	// OnStale is called when a cached response is served because Tracker cannot be reached
+	OnStale func(fetchedAt time.Time, err error)
+}
+
+func NewClient(token string) *Client {
+	return &Client{
+		Client:  &http.Client{},
+		BaseURL: DefaultBaseURL,
+		Token:   token,
+	}
+}
+
+// Error is an error response of the Tracker API.
+type Error struct {
+	StatusCode     int    `json:"-"`
+	Code           string `json:"code"`
+	Message        string `json:"error"`
+	GeneralProblem string `json:"general_problem"`
+	PossibleFix    string `json:"possible_fix"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("Pivotal Tracker responded with %d", e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.GeneralProblem != "" {
+		msg += ", " + e.GeneralProblem
+	}
+	return msg
+}
+
+// IsNotFound reports whether err is a Tracker response for a resource that does not exist.
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	endpoint := c.url(path)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.send(req, out)
+}
+
+func (c *Client) url(path string) string {
+	return strings.TrimRight(c.BaseURL, "/") + path
+}
+
+// send authenticates and sends req, decoding the response into out.
+func (c *Client) send(req *http.Request, out any) (*http.Response, error) {
+	req.Header.Set("X-TrackerToken", c.Token)
+
+	var cached *CachedResponse
+	key := req.URL.String()
+	if c.Cache != nil && req.Method == http.MethodGet {
+		if entry, ok := c.Cache.Get(key); ok {
+			cached = entry
+			if !c.Refresh {
+				if etag := cached.Header["ETag"]; etag != "" {
+					req.Header.Set("If-None-Match", etag)
+				}
+				if modified := cached.Header["Last-Modified"]; modified != "" {
+					req.Header.Set("If-Modified-Since", modified)
+				}
+			}
+		}
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		err = fmt.Errorf("failed to make request: %v", err)
+		if cached != nil {
+			return c.serveStale(cached, err, out)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		cached.FetchedAt = time.Now()
+		c.Cache.Put(key, cached)
+		return cached.response(), decode(cached.Body, out)
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(bodyBytes, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(bodyBytes))
+		}
+		if cached != nil && resp.StatusCode >= 500 {
+			return c.serveStale(cached, apiErr, out)
+		}
+		return resp, apiErr
+	}
+
+	if c.Cache != nil && req.Method == http.MethodGet {
+		c.Cache.Put(key, newCachedResponse(resp, bodyBytes))
+	}
+	return resp, decode(bodyBytes, out)
+}
+
+// serveStale answers with a cached response when Tracker cannot be reached.
+func (c *Client) serveStale(cached *CachedResponse, err error, out any) (*http.Response, error) {
+	if c.OnStale != nil {
+		c.OnStale(cached.FetchedAt, err)
+	}
+	return cached.response(), decode(cached.Body, out)
+}
+
+func decode(body []byte, out any) error {
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	return nil
+}
+
+// list fetches every page of a paginated collection, appending the items to out.
+func list[T any](ctx context.Context, c *Client, path string, query url.Values, out *[]T) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", strconv.Itoa(pageSize))
+
+	for offset := 0; ; {
+		query.Set("offset", strconv.Itoa(offset))
+
+		var page []T
+		resp, err := c.do(ctx, http.MethodGet, path, query, nil, &page)
+		if err != nil {
+			return err
+		}
+		*out = append(*out, page...)
+
+		// Tracker only paginates some collections, and says so with these headers
+		total, err := strconv.Atoi(resp.Header.Get("X-Tracker-Pagination-Total"))
+		if err != nil || len(page) == 0 {
+			return nil
+		}
+		offset += len(page)
+		if offset >= total {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package pivotal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Iteration scopes stories can be limited to.
+const (
+	CurrentIteration = "current"
+	Backlog          = "backlog"
+	Icebox           = "icebox"
+)
+
+// StoryFilter selects stories with Tracker's search syntax. Empty fields match every story.
+type StoryFilter struct {
+	Owner  string
+	States []string
+	Types  []string
+	// Labels match stories having any of them
+	Labels []string
+	// Query is a raw Tracker search query the stories must also match
+	Query string
+}
+
+// String returns the filter in Tracker's search syntax, e.g. `owner:"thuanngo" AND state:started,finished`.
+func (f StoryFilter) String() string {
+	var parts []string
+	if f.Owner != "" {
+		parts = append(parts, fmt.Sprintf("owner:%q", f.Owner))
+	}
+	if len(f.States) > 0 {
+		parts = append(parts, "state:"+strings.Join(f.States, ","))
+	}
+	if len(f.Types) > 0 {
+		parts = append(parts, "type:"+strings.Join(f.Types, ","))
+	}
+	if len(f.Labels) > 0 {
+		labels := make([]string, len(f.Labels))
+		for i, label := range f.Labels {
+			labels[i] = fmt.Sprintf("label:%q", label)
+		}
+		parts = append(parts, "("+strings.Join(labels, " OR ")+")")
+	}
+	if f.Query != "" {
+		parts = append(parts, "("+f.Query+")")
+	}
+	return strings.Join(parts, " AND ")
+}
@@ -0,0 +1,114 @@
+package pivotal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// acceptanceHeading matches the line starting the acceptance criteria in a description, e.g. "## Acceptance Criteria" or "AC:".
+var acceptanceHeading = regexp.MustCompile(`(?im)^[#*_ ]*(acceptance criteria|AC)\b[*_: ]*$`)
+
+var markdownHeading = regexp.MustCompile(`(?m)^#+ `)
+
+// SplitAcceptanceCriteria separates the acceptance criteria section from the rest of a description.
+func SplitAcceptanceCriteria(description string) (string, string) {
+	loc := acceptanceHeading.FindStringIndex(description)
+	if loc == nil {
+		return strings.TrimSpace(description), ""
+	}
+
+	before := description[:loc[0]]
+	criteria := description[loc[1]:]
+	// The section ends at the next Markdown heading
+	if next := markdownHeading.FindStringIndex(criteria); next != nil {
+		before += criteria[next[0]:]
+		criteria = criteria[:next[0]]
+	}
+	return strings.TrimSpace(before), strings.TrimSpace(criteria)
+}
+
+// EstimateText returns the estimate of a story in points, or "unestimated".
+func (s *Story) EstimateText() string {
+	if s.Estimate == nil {
+		return "unestimated"
+	}
+	points := strconv.FormatFloat(*s.Estimate, 'f', -1, 64)
+	if points == "1" {
+		return "1 point"
+	}
+	return points + " points"
+}
+
+// PersonName returns the name of the person with the given ID among people, or their ID.
+func PersonName(people []Person, id int) string {
+	for _, p := range people {
+		if p.ID == id {
+			return p.Name
+		}
+	}
+	return fmt.Sprintf("#%d", id)
+}
+
+// Markdown renders a story, with its tasks, blockers and comments, as a Markdown document. People are used to name the owners and commenters.
+func Markdown(story *Story, people []Person) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", story.Name)
+	fmt.Fprintf(&b, "- Story: #%d, %s\n", story.ID, story.URL)
+	fmt.Fprintf(&b, "- Type: %s, %s\n", story.StoryType, story.EstimateText())
+	fmt.Fprintf(&b, "- State: %s\n", story.CurrentState)
+	if len(story.OwnerIDs) > 0 {
+		owners := make([]string, len(story.OwnerIDs))
+		for i, id := range story.OwnerIDs {
+			owners[i] = PersonName(people, id)
+		}
+		fmt.Fprintf(&b, "- Owners: %s\n", strings.Join(owners, ", "))
+	}
+	if len(story.Labels) > 0 {
+		fmt.Fprintf(&b, "- Labels: %s\n", strings.Join(story.LabelNames(), ", "))
+	}
+
+	description, criteria := SplitAcceptanceCriteria(story.Description)
+	if description != "" {
+		fmt.Fprintf(&b, "\n## Description\n\n%s\n", description)
+	}
+	if criteria != "" {
+		fmt.Fprintf(&b, "\n## Acceptance Criteria\n\n%s\n", criteria)
+	}
+
+	if len(story.Tasks) > 0 {
+		b.WriteString("\n## Tasks\n\n")
+		for _, task := range story.Tasks {
+			check := " "
+			if task.Complete {
+				check = "x"
+			}
+			fmt.Fprintf(&b, "- [%s] %s\n", check, task.Description)
+		}
+	}
+
+	if len(story.Blockers) > 0 {
+		b.WriteString("\n## Blockers\n\n")
+		for _, blocker := range story.Blockers {
+			status := ""
+			if blocker.Resolved {
+				status = " (resolved)"
+			}
+			fmt.Fprintf(&b, "- %s%s\n", blocker.Description, status)
+		}
+	}
+
+	if len(story.Comments) > 0 {
+		b.WriteString("\n## Comments\n")
+		for _, comment := range story.Comments {
+			if strings.TrimSpace(comment.Text) == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "\n**%s** on %s:\n\n%s\n", PersonName(people, comment.PersonID), comment.CreatedAt.Format("2006-01-02"), strings.TrimSpace(comment.Text))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package pivotal
+
+import (
+	"context"
+	"net/http"
+)
+
+// Me is the user an API token belongs to.
+type Me struct {
+	Person
+	Projects []MembershipSummary `json:"projects"`
+}
+
+// MembershipSummary is a project the user is a member of.
+type MembershipSummary struct {
+	ProjectID   int    `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	Role        string `json:"role"`
+}
+
+// ProjectIDs returns the IDs of the projects the user is a member of.
+func (m *Me) ProjectIDs() []int {
+	ids := make([]int, len(m.Projects))
+	for i, p := range m.Projects {
+		ids[i] = p.ProjectID
+	}
+	return ids
+}
+
+// Me returns the user the API token belongs to, with their projects.
+func (c *Client) Me(ctx context.Context) (*Me, error) {
+	var me Me
+	if _, err := c.do(ctx, http.MethodGet, "/me", nil, nil, &me); err != nil {
+		return nil, err
+	}
+	return &me, nil
+}
@@ -0,0 +1,33 @@
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type Person struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Initials string `json:"initials"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type membership struct {
+	Person Person `json:"person"`
+}
+
+// Members returns the people of a project.
+func (c *Client) Members(ctx context.Context, projectID int) ([]Person, error) {
+	var memberships []membership
+	if _, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d/memberships", projectID), nil, nil, &memberships); err != nil {
+		return nil, err
+	}
+
+	people := make([]Person, len(memberships))
+	for i, m := range memberships {
+		people[i] = m.Person
+	}
+	return people, nil
+}
@@ -0,0 +1,64 @@
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type Project struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// PointScale lists the estimates stories can be given, e.g. "0,1,2,3"
+	PointScale                  string `json:"point_scale"`
+	BugsAndChoresAreEstimatable bool   `json:"bugs_and_chores_are_estimatable"`
+}
+
+// Points returns the estimates of the point scale of the project.
+func (p *Project) Points() []float64 {
+	var points []float64
+	for _, field := range strings.Split(p.PointScale, ",") {
+		if point, err := strconv.ParseFloat(strings.TrimSpace(field), 64); err == nil {
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+type iteration struct {
+	Number  int     `json:"number"`
+	Stories []Story `json:"stories"`
+}
+
+// Project returns a single project.
+func (c *Client) Project(ctx context.Context, projectID int) (*Project, error) {
+	var project Project
+	if _, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d", projectID), nil, nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// IterationStoryIDs returns the IDs of the stories in the current iteration or in the backlog of a project.
+func (c *Client) IterationStoryIDs(ctx context.Context, projectID int, scope string) (map[int]bool, error) {
+	if scope != CurrentIteration && scope != Backlog {
+		return nil, fmt.Errorf("unknown iteration %q, expected %s or %s", scope, CurrentIteration, Backlog)
+	}
+
+	var iterations []iteration
+	query := url.Values{"scope": {scope}, "fields": {"number,stories(id)"}}
+	if err := list(ctx, c, fmt.Sprintf("/projects/%d/iterations", projectID), query, &iterations); err != nil {
+		return nil, err
+	}
+
+	ids := map[int]bool{}
+	for _, it := range iterations {
+		for _, story := range it.Stories {
+			ids[story.ID] = true
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,188 @@
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Story types
+const (
+	Feature = "feature"
+	Bug     = "bug"
+	Chore   = "chore"
+	Release = "release"
+)
+
+// Story states
+const (
+	Unscheduled = "unscheduled"
+	Unstarted   = "unstarted"
+	Planned     = "planned"
+	Started     = "started"
+	Finished    = "finished"
+	Delivered   = "delivered"
+	Rejected    = "rejected"
+	Accepted    = "accepted"
+)
+
+// storyFields asks Tracker to include the nested resources of the Story model.
+const storyFields = ":default,tasks,comments,blockers"
+
+type Story struct {
+	ID            int        `json:"id"`
+	ProjectID     int        `json:"project_id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	StoryType     string     `json:"story_type"`
+	CurrentState  string     `json:"current_state"`
+	Estimate      *float64   `json:"estimate,omitempty"`
+	Labels        []Label    `json:"labels"`
+	OwnerIDs      []int      `json:"owner_ids"`
+	RequestedByID int        `json:"requested_by_id"`
+	Tasks         []Task     `json:"tasks"`
+	Comments      []Comment  `json:"comments"`
+	Blockers      []Blocker  `json:"blockers"`
+	URL           string     `json:"url"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	AcceptedAt    *time.Time `json:"accepted_at,omitempty"`
+}
+
+type Label struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Task struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+	Complete    bool   `json:"complete"`
+	Position    int    `json:"position"`
+}
+
+type Comment struct {
+	ID              int              `json:"id"`
+	PersonID        int              `json:"person_id"`
+	Text            string           `json:"text"`
+	FileAttachments []FileAttachment `json:"file_attachments,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+// NewComment is a comment to add to a story. FileAttachments are files previously sent with Upload.
+type NewComment struct {
+	Text            string           `json:"text,omitempty"`
+	FileAttachments []FileAttachment `json:"file_attachments,omitempty"`
+}
+
+type Blocker struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+	Resolved    bool   `json:"resolved"`
+}
+
+// LabelNames returns the names of the labels of the story.
+func (s *Story) LabelNames() []string {
+	names := make([]string, len(s.Labels))
+	for i, label := range s.Labels {
+		names[i] = label.Name
+	}
+	return names
+}
+
+// StoryUpdate holds the fields of a story to change. Nil fields are left as they are.
+type StoryUpdate struct {
+	Name         *string  `json:"name,omitempty"`
+	Description  *string  `json:"description,omitempty"`
+	CurrentState *string  `json:"current_state,omitempty"`
+	Estimate     *float64 `json:"estimate,omitempty"`
+	OwnerIDs     []int    `json:"owner_ids,omitempty"`
+}
+
+// NewStory is the body of a request creating a story.
+type NewStory struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	StoryType   string   `json:"story_type,omitempty"`
+	Estimate    *float64 `json:"estimate,omitempty"`
+}
+
+// StoryURL returns the address of a story in the Tracker web app.
+func StoryURL(storyID int) string {
+	return fmt.Sprintf("https://www.pivotaltracker.com/story/show/%d", storyID)
+}
+
+func storiesPath(projectID int) string {
+	return fmt.Sprintf("/projects/%d/stories", projectID)
+}
+
+func storyPath(projectID, storyID int) string {
+	return fmt.Sprintf("%s/%d", storiesPath(projectID), storyID)
+}
+
+// Stories returns every story of a project matching a search filter, such as `owner:"thuanngo" AND state:started`.
+func (c *Client) Stories(ctx context.Context, projectID int, filter string) ([]Story, error) {
+	query := url.Values{}
+	query.Set("fields", storyFields)
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+
+	var stories []Story
+	if err := list(ctx, c, storiesPath(projectID), query, &stories); err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+// Story returns a single story of a project.
+func (c *Client) Story(ctx context.Context, projectID, storyID int) (*Story, error) {
+	var story Story
+	query := url.Values{"fields": {storyFields}}
+	if _, err := c.do(ctx, http.MethodGet, storyPath(projectID, storyID), query, nil, &story); err != nil {
+		return nil, err
+	}
+	return &story, nil
+}
+
+// StoryByID returns a story of any project the user can access.
+func (c *Client) StoryByID(ctx context.Context, storyID int) (*Story, error) {
+	var story Story
+	query := url.Values{"fields": {storyFields}}
+	if _, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/stories/%d", storyID), query, nil, &story); err != nil {
+		return nil, err
+	}
+	return &story, nil
+}
+
+// UpdateStory changes the fields of a story set in update and returns the updated story.
+func (c *Client) UpdateStory(ctx context.Context, projectID, storyID int, update StoryUpdate) (*Story, error) {
+	var story Story
+	query := url.Values{"fields": {storyFields}}
+	if _, err := c.do(ctx, http.MethodPut, storyPath(projectID, storyID), query, update, &story); err != nil {
+		return nil, err
+	}
+	return &story, nil
+}
+
+// CreateStory adds a story to the icebox of a project.
+func (c *Client) CreateStory(ctx context.Context, projectID int, story NewStory) (*Story, error) {
+	var created Story
+	query := url.Values{"fields": {storyFields}}
+	if _, err := c.do(ctx, http.MethodPost, storiesPath(projectID), query, story, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// CreateComment adds a comment to a story.
+func (c *Client) CreateComment(ctx context.Context, projectID, storyID int, comment NewComment) (*Comment, error) {
+	var created Comment
+	query := url.Values{"fields": {":default,file_attachments"}}
+	if _, err := c.do(ctx, http.MethodPost, storyPath(projectID, storyID)+"/comments", query, comment, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
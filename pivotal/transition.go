@@ -0,0 +1,52 @@
+package pivotal
+
+import (
+	"fmt"
+	"slices"
+)
+
+// transition describes a state change: the states a story can be moved from and the story types it applies to.
+type transition struct {
+	from  []string
+	types []string
+}
+
+var transitions = map[string]transition{
+	Started:   {from: []string{Unscheduled, Unstarted, Planned, Rejected}, types: []string{Feature, Bug, Chore, Release}},
+	Finished:  {from: []string{Started}, types: []string{Feature, Bug}},
+	Delivered: {from: []string{Finished}, types: []string{Feature, Bug}},
+	Rejected:  {from: []string{Delivered}, types: []string{Feature, Bug}},
+	// Chores and releases are accepted as soon as they are done
+	Accepted: {from: []string{Delivered, Started}, types: []string{Feature, Bug, Chore, Release}},
+}
+
+// FromStates returns the states a story can be moved to state from.
+func FromStates(state string) []string {
+	return transitions[state].from
+}
+
+// CheckTransition reports why story cannot be moved to state, if it cannot.
+func CheckTransition(story *Story, state string) error {
+	t, ok := transitions[state]
+	if !ok {
+		return fmt.Errorf("unknown story state %q", state)
+	}
+	if !slices.Contains(t.types, story.StoryType) {
+		return fmt.Errorf("%ss cannot be %s", story.StoryType, state)
+	}
+	if state == Accepted && story.CurrentState == Started && (story.StoryType == Feature || story.StoryType == Bug) {
+		return fmt.Errorf("%ss must be finished and delivered before they are accepted", story.StoryType)
+	}
+	if !slices.Contains(t.from, story.CurrentState) {
+		return fmt.Errorf("story #%d is %s and cannot be %s", story.ID, story.CurrentState, state)
+	}
+	if state == Started && story.StoryType == Feature && story.Estimate == nil {
+		return fmt.Errorf("story #%d must be estimated before it is started", story.ID)
+	}
+	return nil
+}
+
+// CanTransition reports whether story can be moved to state.
+func CanTransition(story *Story, state string) bool {
+	return CheckTransition(story, state) == nil
+}
@@ -0,0 +1,138 @@
+package pivotal
+
+import "testing"
+
+func TestCheckTransition(t *testing.T) {
+	points := 2.0
+
+	tests := []struct {
+		name    string
+		story   Story
+		state   string
+		allowed bool
+	}{
+		{
+			name:    "estimated feature started",
+			story:   Story{StoryType: Feature, CurrentState: Unstarted, Estimate: &points},
+			state:   Started,
+			allowed: true,
+		},
+		{
+			name:    "unestimated feature started",
+			story:   Story{StoryType: Feature, CurrentState: Unstarted},
+			state:   Started,
+			allowed: false,
+		},
+		{
+			name:    "unestimated bug started",
+			story:   Story{StoryType: Bug, CurrentState: Unstarted},
+			state:   Started,
+			allowed: true,
+		},
+		{
+			name:    "unestimated chore started",
+			story:   Story{StoryType: Chore, CurrentState: Unscheduled},
+			state:   Started,
+			allowed: true,
+		},
+		{
+			name:    "started story started again",
+			story:   Story{StoryType: Bug, CurrentState: Started},
+			state:   Started,
+			allowed: false,
+		},
+		{
+			name:    "feature finished",
+			story:   Story{StoryType: Feature, CurrentState: Started, Estimate: &points},
+			state:   Finished,
+			allowed: true,
+		},
+		{
+			name:    "chore finished",
+			story:   Story{StoryType: Chore, CurrentState: Started},
+			state:   Finished,
+			allowed: false,
+		},
+		{
+			name:    "unstarted bug finished",
+			story:   Story{StoryType: Bug, CurrentState: Unstarted},
+			state:   Finished,
+			allowed: false,
+		},
+		{
+			name:    "bug delivered",
+			story:   Story{StoryType: Bug, CurrentState: Finished},
+			state:   Delivered,
+			allowed: true,
+		},
+		{
+			name:    "chore delivered",
+			story:   Story{StoryType: Chore, CurrentState: Finished},
+			state:   Delivered,
+			allowed: false,
+		},
+		{
+			name:    "release delivered",
+			story:   Story{StoryType: Release, CurrentState: Started},
+			state:   Delivered,
+			allowed: false,
+		},
+		{
+			name:    "delivered feature accepted",
+			story:   Story{StoryType: Feature, CurrentState: Delivered, Estimate: &points},
+			state:   Accepted,
+			allowed: true,
+		},
+		{
+			name:    "started feature accepted",
+			story:   Story{StoryType: Feature, CurrentState: Started, Estimate: &points},
+			state:   Accepted,
+			allowed: false,
+		},
+		{
+			name:    "started bug accepted",
+			story:   Story{StoryType: Bug, CurrentState: Started},
+			state:   Accepted,
+			allowed: false,
+		},
+		{
+			name:    "finished feature accepted",
+			story:   Story{StoryType: Feature, CurrentState: Finished, Estimate: &points},
+			state:   Accepted,
+			allowed: false,
+		},
+		{
+			name:    "started chore accepted",
+			story:   Story{StoryType: Chore, CurrentState: Started},
+			state:   Accepted,
+			allowed: true,
+		},
+		{
+			name:    "started release accepted",
+			story:   Story{StoryType: Release, CurrentState: Started},
+			state:   Accepted,
+			allowed: true,
+		},
+		{
+			name:    "delivered bug rejected",
+			story:   Story{StoryType: Bug, CurrentState: Delivered},
+			state:   Rejected,
+			allowed: true,
+		},
+		{
+			name:    "unknown state",
+			story:   Story{StoryType: Feature, CurrentState: Started, Estimate: &points},
+			state:   "done",
+			allowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTransition(&tt.story, tt.state)
+			if allowed := err == nil; allowed != tt.allowed {
+				t.Errorf("CheckTransition(%s %s, %s) = %v, want allowed %v", tt.story.CurrentState, tt.story.StoryType, tt.state, err, tt.allowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package pivotal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// FileAttachment is a file uploaded to a project, to be attached to a comment.
+type FileAttachment struct {
+	ID          int    `json:"id"`
+	Kind        string `json:"kind,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// Upload sends a file to a project, so that it can be attached to a comment.
+func (c *Client) Upload(ctx context.Context, projectID int, filename string, content io.Reader) (*FileAttachment, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(fmt.Sprintf("/projects/%d/uploads", projectID)), &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	var attachment FileAttachment
+	if _, err := c.send(req, &attachment); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -0,0 +1,268 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+var codeTemplate = template.Must(template.New("code").Parse(`I'm working on the task below:
+
+<Task requirement>
+</Task requirement>
+
+Below is the relevant source code that I have in my repo:
+
+` + "```" + `
+
+` + "```" + `
+
+**General Instructions:**
+0. You are a senior developer who values best practices and always produces good, clean code.
+1. Let's implement the task step by step. I will need to adjust your solution along the way.
+2. Ensure the output is production-ready quality code that is clean, optimized, and maintainable.
+3. The code should follow best practices and adhere to the project's coding standards.
+`))
+
+// Code renders the prompt template for implementing a task.
+func Code() (string, error) {
+	return render(codeTemplate, nil)
+}
+
+// CommitData is the data available to the commit message template.
+type CommitData struct {
+	Diff string
+	// Excluded lists the changed files whose content is left out of Diff
+	Excluded string
+	// Summaries replaces Diff when the diff is too large to be sent at once
+	Summaries string
+	// StoryID is the Tracker story the changes are for, 0 when unknown
+	StoryID  int
+	StoryURL string
+}
+
+// diffTemplate shows the diff, or the summaries of its parts when it was too large.
+const diffTemplate = `{{define "diff"}}
+{{- if .Summaries -}}
+The diff is too large to be shown at once, these are summaries of its parts:
+
+{{.Summaries}}
+{{- else -}}
+` + "```diff" + `
+{{.Diff}}
+` + "```" + `
+{{- end}}
+{{- end}}`
+
+var commitTemplate = template.Must(template.Must(template.New("commit").Parse(diffTemplate)).Parse(`Please generate descriptive commit message for the following changes:
+
+{{template "diff" .}}
+{{- with .Excluded}}
+
+{{.}}
+{{- end}}
+
+Just output the commit message, do not wrap it in anything.
+
+The first line of the commit message should be a concise name for the commit.
+{{- with .StoryID}} End it with [#{{.}}], the Tracker story the changes are for.{{end}}
+Then in the body, we provide more context about the change in form of list, start with a dash.
+For example:
+
+This is a concise name of the commit
+
+- Add a new user model
+- Refactor views
+`))
+
+// PRData is the data available to the pull request template.
+type PRData struct {
+	Base string
+	Log  string
+	Diff string
+	// Excluded lists the changed files whose content is left out of Diff
+	Excluded string
+	// Summaries replaces Diff when the diff is too large to be sent at once
+	Summaries string
+	// StoryID is the Tracker story the changes are for, 0 when unknown
+	StoryID  int
+	StoryURL string
+}
+
+var prTemplate = template.Must(template.Must(template.New("pr").Parse(diffTemplate)).Parse(`Help me generate a PR title and description for the below changes against the {{.Base}} branch.
+
+The commits in this branch are:
+` + "```" + `
+{{.Log}}
+` + "```" + `
+
+The changes are:
+
+{{template "diff" .}}
+{{- with .Excluded}}
+
+{{.}}
+{{- end}}
+
+Just output the title on the first line, followed by an empty line and the description. Do not wrap it in anything.
+{{- with .StoryURL}}
+Start the description with a link to the story the changes are for: {{.}}
+{{- end}}
+
+Note that the format of the description should follow this one:
+
+` + "```" + `
+## Description
+This Pull Request introduces several key functionalities aimed at enhancing the user authentication and verification process in the SkyDeck Control Center (CC). Specifically, it allows users to sign up using email and password, restricts access until SMS verification is completed, and sets up a webhook to handle inbound SMS verification messages from Twilio. Additionally, it provides users with the ability to confirm the submission of their verification SMS.
+
+## Summary of Changes
+1. **Email and Password Signup for Control Center**:
+   - Configured necessary settings in ` + "`settings.py`" + ` for handling signups.
+   - Updated the signup template to include terms of use and privacy policy agreements.
+
+2. **Restrict Access Until SMS Verification**:
+   - Implemented middleware to redirect users to the verification instruction page if they are not verified.
+   - Created views and templates for displaying SMS verification instructions.
+
+3. **Additional Updates**:
+   - Added tests for new models, views, and middleware to ensure robust functionality.
+   - Updated styling and templates to improve user experience during the signup and verification process.
+` + "```" + `
+`))
+
+// PR renders the prompt asking for a pull request title and description.
+func PR(data PRData) (string, error) {
+	return render(prTemplate, data)
+}
+
+// SummaryData is the data available to the template summarizing part of a large diff.
+type SummaryData struct {
+	Part  int
+	Parts int
+	Diff  string
+}
+
+var summaryTemplate = template.Must(template.New("summary").Parse(`This is part {{.Part}} of {{.Parts}} of a large diff:
+
+` + "```diff" + `
+{{.Diff}}
+` + "```" + `
+
+Summarize the changes in this part so that a commit message or pull request description can be written from the summaries of all parts.
+For every file, state whether it was added, deleted or modified and list its notable changes as short bullet points.
+Just output the summary, do not add an introduction.
+`))
+
+// Summary renders the prompt asking for the summary of one part of a large diff.
+func Summary(data SummaryData) (string, error) {
+	return render(summaryTemplate, data)
+}
+
+var condenseTemplate = template.Must(template.New("condense").Parse(`These are summaries of the parts of a large diff, group {{.Part}} of {{.Parts}}:
+
+{{.Diff}}
+
+They are still too long to write a commit message or pull request description from. Merge them into a single, shorter summary.
+Keep every added or deleted file and the most notable changes, and drop minor details.
+Just output the summary, do not add an introduction.
+`))
+
+// Condense renders the prompt asking to shorten summaries of the parts of a large diff. Diff holds the summaries.
+func Condense(data SummaryData) (string, error) {
+	return render(condenseTemplate, data)
+}
+
+// StoryData is the data available to the template turning notes into a Tracker story.
+type StoryData struct {
+	Notes string
+	// Points lists the estimates allowed in the project
+	Points string
+}
+
+var storyTemplate = template.Must(template.New("story").Parse(`Turn these rough notes into a Pivotal Tracker story:
+
+` + "```" + `
+{{.Notes}}
+` + "```" + `
+
+Write a short title and a description in Markdown explaining the goal of the story and its context.
+List the acceptance criteria separately, as short statements that can be checked once the story is done.
+Pick the story type: "feature" for new behavior, "bug" for something broken, "chore" for work that brings no direct value to users.
+Suggest an estimate for features{{with .Points}}, one of {{.}}{{end}}, or null when the notes are too vague.
+
+Just output a JSON object with the fields "title", "description", "acceptance_criteria" (an array of strings), "story_type" and "estimate", do not wrap it in anything.
+`))
+
+// Story renders the prompt asking for a story drafted from rough notes.
+func Story(data StoryData) (string, error) {
+	return render(storyTemplate, data)
+}
+
+// PlanData is the data available to the template asking for an implementation plan.
+type PlanData struct {
+	// Story is the story to implement, in Markdown
+	Story string
+	Repo  string
+	// Map is the tree of the files of the repository
+	Map string
+}
+
+var planTemplate = template.Must(template.New("plan").Parse(`I'm working on the story below in the {{.Repo}} repository:
+
+{{.Story}}
+
+These are the files of the repository:
+
+` + "```" + `
+{{.Map}}
+` + "```" + `
+
+Write an implementation plan for the story:
+1. Summarize what has to be done in a few sentences, and list any question the story leaves open.
+2. List the steps to implement it in order. For every step, name the files to add or change and what changes in them.
+3. Explain how to test the changes, covering every acceptance criterion.
+4. Point out risks, such as migrations, breaking changes or performance concerns.
+
+You are a senior developer who values best practices. Follow the conventions the repository already uses.
+I will ask you for the code of each step afterwards, so do not write the full implementation yet.
+`))
+
+// Plan renders the prompt asking for a plan to implement a story.
+func Plan(data PlanData) (string, error) {
+	return render(planTemplate, data)
+}
+
+// Commit renders the prompt asking for a commit message describing the diff.
+func Commit(data CommitData) (string, error) {
+	return render(commitTemplate, data)
+}
+
+// Override replaces the built-in code, commit or pr template with a custom
+// text/template. Commit and pr templates can use {{template "diff" .}} to
+// show the diff, or its summaries when it was too large.
+func Override(name, text string) error {
+	tmpl, err := template.Must(template.New(name).Parse(diffTemplate)).Parse(text)
+	if err != nil {
+		return fmt.Errorf("error parsing %s prompt template: %w", name, err)
+	}
+
+	switch name {
+	case "code":
+		codeTemplate = tmpl
+	case "commit":
+		commitTemplate = tmpl
+	case "pr":
+		prTemplate = tmpl
+	default:
+		return fmt.Errorf("unknown prompt template %q", name)
+	}
+	return nil
+}
+
+func render(tmpl *template.Template, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
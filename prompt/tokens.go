@@ -0,0 +1,120 @@
+package prompt
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultTokenBudget is the number of tokens a single message may use when no budget is configured.
+const DefaultTokenBudget = 16000
+
+// charsPerToken is deliberately lower than the usual 4 characters per token
+// of English prose, as diffs are full of symbols and short identifiers.
+const charsPerToken = 3
+
+// EstimateTokens returns a rough, conservative estimate of the number of tokens in text.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// Chunk groups the patches into chunks of at most budget tokens each. Patches
+// larger than the budget are split at hunk boundaries, and hunks that are
+// still too large are truncated.
+func Chunk(patches []string, budget int) []string {
+	var chunks []string
+	var current strings.Builder
+	currentTokens := 0
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentTokens = 0
+		}
+	}
+
+	for _, patch := range patches {
+		for _, part := range splitPatch(patch, budget) {
+			tokens := EstimateTokens(part)
+			if currentTokens+tokens > budget {
+				flush()
+			}
+			if current.Len() > 0 {
+				current.WriteString("\n")
+			}
+			current.WriteString(part)
+			currentTokens += tokens
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// splitPatch splits the patch of a single file into parts that fit the
+// budget. Every part repeats the file header so that it can be understood on its own.
+func splitPatch(patch string, budget int) []string {
+	if EstimateTokens(patch) <= budget {
+		return []string{patch}
+	}
+
+	lines := strings.Split(patch, "\n")
+	header := lines
+	var hunks [][]string
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			if len(hunks) == 0 {
+				header = lines[:i]
+			}
+			hunks = append(hunks, nil)
+		}
+		if len(hunks) > 0 {
+			hunks[len(hunks)-1] = append(hunks[len(hunks)-1], line)
+		}
+	}
+
+	headerText := strings.Join(header, "\n")
+	hunkBudget := budget - EstimateTokens(headerText)
+
+	var parts []string
+	var current []string
+	currentTokens := 0
+	for _, hunk := range hunks {
+		hunkText := truncate(strings.Join(hunk, "\n"), hunkBudget)
+		tokens := EstimateTokens(hunkText)
+		if len(current) > 0 && currentTokens+tokens > hunkBudget {
+			parts = append(parts, headerText+"\n"+strings.Join(current, "\n"))
+			current, currentTokens = nil, 0
+		}
+		current = append(current, hunkText)
+		currentTokens += tokens
+	}
+	if len(current) > 0 || len(parts) == 0 {
+		parts = append(parts, truncate(headerText+"\n"+strings.Join(current, "\n"), budget))
+	}
+
+	return parts
+}
+
+// truncate cuts text at a line boundary so that it fits in budget tokens.
+func truncate(text string, budget int) string {
+	const marker = "\n[... truncated ...]"
+	if EstimateTokens(text) <= budget {
+		return text
+	}
+
+	limit := (budget - EstimateTokens(marker)) * charsPerToken
+	if limit < 0 {
+		limit = 0
+	}
+	runes := []rune(text)
+	if limit > len(runes) {
+		limit = len(runes)
+	}
+
+	cut := string(runes[:limit])
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + marker
+}
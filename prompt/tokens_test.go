@@ -0,0 +1,154 @@
+package prompt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// lines returns n lines of width characters each, joined by newlines.
+func lines(n, width int) string {
+	line := strings.Repeat("a", width)
+	return strings.TrimSuffix(strings.Repeat(line+"\n", n), "\n")
+}
+
+const header = "diff --git a/f.go b/f.go\n--- a/f.go\n+++ b/f.go"
+
+func hunk(n int) string {
+	return "@@ -1 +1 @@\n" + lines(n, 29)
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		budget int
+		want   string
+	}{
+		{
+			name:   "fits",
+			text:   "short text",
+			budget: 10,
+			want:   "short text",
+		},
+		{
+			name:   "cut at a line boundary",
+			text:   lines(10, 9),
+			budget: 20,
+			want:   lines(3, 9) + "\n[... truncated ...]",
+		},
+		{
+			name:   "budget smaller than the marker",
+			text:   lines(10, 9),
+			budget: 2,
+			want:   "\n[... truncated ...]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncate(tt.text, tt.budget); got != tt.want {
+				t.Errorf("truncate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		patch  string
+		budget int
+		want   []string
+	}{
+		{
+			name:   "fits",
+			patch:  header + "\n" + hunk(2),
+			budget: 100,
+			want:   []string{header + "\n" + hunk(2)},
+		},
+		{
+			name:   "split at hunk boundaries with the header repeated",
+			patch:  header + "\n" + hunk(5) + "\n" + hunk(5),
+			budget: 80,
+			want:   []string{header + "\n" + hunk(5), header + "\n" + hunk(5)},
+		},
+		{
+			name:   "hunks grouped while they fit",
+			patch:  header + "\n" + hunk(1) + "\n" + hunk(1) + "\n" + hunk(5),
+			budget: 80,
+			want:   []string{header + "\n" + hunk(1) + "\n" + hunk(1), header + "\n" + hunk(5)},
+		},
+		{
+			name:   "oversized hunk truncated",
+			patch:  header + "\n" + hunk(20),
+			budget: 80,
+			want:   []string{header + "\n" + "@@ -1 +1 @@\n" + lines(5, 29) + "\n[... truncated ...]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitPatch(tt.patch, tt.budget)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitPatch() = %q, want %q", got, tt.want)
+			}
+			for _, part := range got {
+				if tokens := EstimateTokens(part); tokens > tt.budget {
+					t.Errorf("part of %d tokens exceeds the budget of %d: %q", tokens, tt.budget, part)
+				}
+			}
+		})
+	}
+}
+
+func TestChunk(t *testing.T) {
+	small := header + "\n" + hunk(1)
+	large := header + "\n" + hunk(5) + "\n" + hunk(5)
+
+	tests := []struct {
+		name    string
+		patches []string
+		budget  int
+		want    []string
+	}{
+		{
+			name:    "no patches",
+			patches: nil,
+			budget:  80,
+			want:    nil,
+		},
+		{
+			name:    "small patches grouped",
+			patches: []string{small, small},
+			budget:  80,
+			want:    []string{small + "\n" + small},
+		},
+		{
+			name:    "new chunk when the budget is reached",
+			patches: []string{small, small, small},
+			budget:  80,
+			want:    []string{small + "\n" + small, small},
+		},
+		{
+			name:    "large patch split",
+			patches: []string{large},
+			budget:  80,
+			want:    []string{header + "\n" + hunk(5), header + "\n" + hunk(5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Chunk(tt.patches, tt.budget)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chunk() = %q, want %q", got, tt.want)
+			}
+			for _, chunk := range got {
+				if tokens := EstimateTokens(chunk); tokens > tt.budget {
+					t.Errorf("chunk of %d tokens exceeds the budget of %d", tokens, tt.budget)
+				}
+			}
+		})
+	}
+}
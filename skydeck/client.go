@@ -8,14 +8,13 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
-	"os"
 )
 
-type SkyDeckClient struct{}
-
 const (
 	BaseURL     = "https://admin.skydeck.ai"
 	ReferrerURL = "https://eastagile.skydeck.ai/"
+
+	DefaultModelID = 4094
 )
 
 type SendMessagePayload struct {
@@ -26,18 +25,37 @@ type SendMessagePayload struct {
 	NonAI               bool   `json:"non_ai"`
 }
 
+type Message struct {
+	ID        int    `json:"id"`
+	Type      string `json:"type"`
+	Content   string `json:"content"`
+	Streaming bool   `json:"streaming"`
+}
+
 type SendMessageResponse struct {
 	Data struct {
-		ConversationID       int                    `json:"conversation_id"`
-		AssistantMessageID   int                    `json:"assistant_message_id"`
-		RememberizerAPIQuery map[string]interface{} `json:"rememberizer_api_query"`
+		ConversationID int       `json:"conversation_id"`
+		Messages       []Message `json:"messages"`
 	} `json:"data"`
 }
 
+// AssistantMessageID returns the ID of the assistant message that is being streamed.
+func (r *SendMessageResponse) AssistantMessageID() int {
+	for _, msg := range r.Data.Messages {
+		if msg.Type == "assistant" && msg.Streaming {
+			return msg.ID
+		}
+	}
+	return 0
+}
+
 type APIClient struct {
 	Client       *http.Client
 	AccessToken  string
 	RefreshToken string
+
+	// OnTokenRefresh is called with the new access token whenever the client refreshes it.
+	OnTokenRefresh func(accessToken string) error
 }
 
 func NewAPIClient(accessToken, refreshToken string) *APIClient {
@@ -51,7 +69,57 @@ func NewAPIClient(accessToken, refreshToken string) *APIClient {
 	}
 }
 
-func (api *APIClient) sendMessage(payload SendMessagePayload) (*SendMessageResponse, error) {
+// ConversationURL returns the web URL of a conversation.
+func ConversationURL(conversationID int) string {
+	return fmt.Sprintf("%sconversations/%d", ReferrerURL, conversationID)
+}
+
+// NewMessage builds the payload of a regular chat message.
+func NewMessage(message string, modelID int, conversationID *int) SendMessagePayload {
+	return SendMessagePayload{
+		Message:             message,
+		ModelID:             modelID,
+		ConversationID:      conversationID,
+		RegenerateMessageID: -1,
+		NonAI:               false,
+	}
+}
+
+// Chat sends a message and writes the assistant reply to w. It returns the ID
+// of the conversation the message ended up in.
+func (api *APIClient) Chat(payload SendMessagePayload, w io.Writer) (int, error) {
+	resp, err := api.SendMessage(payload)
+	if err != nil {
+		return 0, fmt.Errorf("error sending message: %v", err)
+	}
+
+	convoID := resp.Data.ConversationID
+	if payload.ConversationID != nil {
+		convoID = *payload.ConversationID
+	}
+
+	assistantMessageID := resp.AssistantMessageID()
+	if assistantMessageID == 0 {
+		return convoID, fmt.Errorf("no streaming assistant message found in the response")
+	}
+
+	if err := api.StreamResponse(assistantMessageID, w); err != nil {
+		return convoID, fmt.Errorf("error getting streaming response: %v", err)
+	}
+
+	return convoID, nil
+}
+
+// Ask sends a message in a new conversation and returns the assistant reply.
+func (api *APIClient) Ask(message string, modelID int) (string, error) {
+	var buf bytes.Buffer
+	if _, err := api.Chat(NewMessage(message, modelID, nil), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (api *APIClient) SendMessage(payload SendMessagePayload) (*SendMessageResponse, error) {
 	url := BaseURL + "/api/v1/conversations/chat_v2/"
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
@@ -110,13 +178,13 @@ func handleResponse(resp *http.Response, api *APIClient, payload SendMessagePayl
 }
 
 func handleUnauthorizedResponse(api *APIClient, payload SendMessagePayload) (*SendMessageResponse, error) {
-	newAccessToken, err := api.refreshTokens()
+	newAccessToken, err := api.RefreshTokens()
 	if err != nil {
 		return nil, fmt.Errorf("error refreshing tokens: %v", err)
 	}
 	api.AccessToken = newAccessToken
 
-	return api.sendMessage(payload)
+	return api.SendMessage(payload)
 }
 
 func readResponseBody(resp *http.Response) string {
@@ -124,19 +192,20 @@ func readResponseBody(resp *http.Response) string {
 	return string(bodyBytes)
 }
 
-func getConversationID(conversationID int, resp *SendMessageResponse) int {
-	if conversationID != 0 {
-		return conversationID
-	}
-	return resp.Data.ConversationID
-}
-
 type StreamingReq struct {
 	MessageID int `json:"message_id"`
 }
 
-func (api *APIClient) getStreamingResponse(messageID int) error {
-	url := BaseURL + fmt.Sprintf("/api/v1/conversations/streaming/")
+type StreamingResponse struct {
+	Data struct {
+		ConversationID int       `json:"conversation_id"`
+		Messages       []Message `json:"messages"`
+	} `json:"data"`
+}
+
+// StreamResponse fetches the content of a streaming assistant message and writes it to w.
+func (api *APIClient) StreamResponse(messageID int, w io.Writer) error {
+	url := BaseURL + "/api/v1/conversations/streaming/"
 
 	payload := StreamingReq{
 		MessageID: messageID,
@@ -161,14 +230,17 @@ func (api *APIClient) getStreamingResponse(messageID int) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		api.AccessToken, err = api.refreshTokens()
+		api.AccessToken, err = api.RefreshTokens()
 		if err != nil {
 			return fmt.Errorf("error refreshing tokens: %v", err)
 		}
 
-		updateAccessToken(api.AccessToken)
+		req, err = http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonPayload))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %v", err)
+		}
+		setRequestHeaders(req, api.AccessToken, api.RefreshToken, "application/json")
 
-		req.AddCookie(&http.Cookie{Name: "eastagile_access", Value: api.AccessToken})
 		resp, err = api.Client.Do(req)
 		if err != nil {
 			return err
@@ -180,11 +252,31 @@ func (api *APIClient) getStreamingResponse(messageID int) error {
 		return fmt.Errorf("received non-200 response code: %d, body: %s", resp.StatusCode, readResponseBody(resp))
 	}
 
-	_, err = io.Copy(os.Stdout, resp.Body)
+	// The response might be the raw streaming content directly
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	// Try to decode as JSON first
+	var streamResp StreamingResponse
+	if err := json.Unmarshal(bodyBytes, &streamResp); err == nil {
+		// If successful JSON decode, find the assistant message
+		for _, msg := range streamResp.Data.Messages {
+			if msg.Type == "assistant" && msg.Streaming {
+				_, err := io.WriteString(w, msg.Content)
+				return err
+			}
+		}
+		return fmt.Errorf("no streaming assistant message found in the response")
+	}
+
+	// If not valid JSON, output the raw response as it's likely the streamed content
+	_, err = w.Write(bodyBytes)
 	return err
 }
 
-func (api *APIClient) refreshTokens() (string, error) {
+func (api *APIClient) RefreshTokens() (string, error) {
 	url := BaseURL + "/api/v1/authentication/token/refresh/"
 	req, err := http.NewRequest(http.MethodPost, url, nil)
 	if err != nil {
@@ -204,7 +296,14 @@ func (api *APIClient) refreshTokens() (string, error) {
 		return "", fmt.Errorf("received non-200 response code: %d, body: %s", resp.StatusCode, readResponseBody(resp))
 	}
 
-	return extractAccessTokenFromCookies(resp.Cookies()), nil
+	accessToken := extractAccessTokenFromCookies(resp.Cookies())
+	if api.OnTokenRefresh != nil {
+		if err := api.OnTokenRefresh(accessToken); err != nil {
+			return "", fmt.Errorf("error saving access token: %v", err)
+		}
+	}
+
+	return accessToken, nil
 }
 
 func extractAccessTokenFromCookies(cookies []*http.Cookie) string {
@@ -0,0 +1,86 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// State holds the data lazyai keeps between runs that is not configuration.
+type State struct {
+	// Bindings maps a conversation scope key to the SkyDeck conversation used in that scope.
+	// Keys of a profile are prefixed with "profile:<name>/".
+	Bindings map[string]int `json:"bindings,omitempty"`
+	// Aliases maps a user chosen name to a SkyDeck conversation, prefixed like Bindings
+	Aliases map[string]int `json:"aliases,omitempty"`
+	// Imported lists the conversations of the deprecated skydeck.convoID setting already bound to the global scope
+	Imported []int `json:"imported,omitempty"`
+
+	path string
+}
+
+// Path returns the location of the state file, following the XDG base directory specification.
+func Path() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "lazyai", "state.json"), nil
+}
+
+// Load reads the state file. A missing file results in an empty state.
+func Load() (*State, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &State{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s.init(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("error parsing state file %s: %w", path, err)
+	}
+	return s.init(), nil
+}
+
+func (s *State) init() *State {
+	if s.Bindings == nil {
+		s.Bindings = map[string]int{}
+	}
+	if s.Aliases == nil {
+		s.Aliases = map[string]int{}
+	}
+	return s
+}
+
+// Save writes the state file, creating its directory when needed.
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("error creating state directory: %w", err)
+	}
+
+	// Write to a temporary file first so that an interrupted write cannot corrupt the state
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("error writing state file: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
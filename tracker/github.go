@@ -0,0 +1,108 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/github"
+)
+
+// GitHub is the issue tracker of a GitHub repository.
+type GitHub struct {
+	Client *github.Client
+	Owner  string
+	Repo   string
+	// Assignee is the login ListMine filters on. When empty, the owner of the token is used.
+	Assignee string
+}
+
+func NewGitHub(client *github.Client, owner, repo, assignee string) *GitHub {
+	return &GitHub{
+		Client:   client,
+		Owner:    owner,
+		Repo:     repo,
+		Assignee: assignee,
+	}
+}
+
+func (g *GitHub) issue(i github.Issue) Issue {
+	return Issue{
+		ID:          strconv.Itoa(i.Number),
+		Title:       i.Title,
+		Description: i.Body,
+		URL:         i.HTMLURL,
+		State:       i.State,
+		Type:        "issue",
+	}
+}
+
+func (g *GitHub) ListMine(ctx context.Context) ([]Issue, error) {
+	assignee := g.Assignee
+	if assignee == "" {
+		if g.Client.Token == "" {
+			return nil, errors.New("github.user must be set, or a GitHub token to find your login")
+		}
+		user, err := g.Client.CurrentUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		assignee = user.Login
+	}
+
+	found, err := g.Client.ListAssignedIssues(ctx, g.Owner, g.Repo, assignee)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, len(found))
+	for i, issue := range found {
+		issues[i] = g.issue(issue)
+	}
+	return issues, nil
+}
+
+func (g *GitHub) Get(ctx context.Context, id string) (*Issue, error) {
+	number, err := issueNumber(id)
+	if err != nil {
+		return nil, err
+	}
+	found, err := g.Client.GetIssue(ctx, g.Owner, g.Repo, number)
+	if err != nil {
+		return nil, err
+	}
+	issue := g.issue(*found)
+	return &issue, nil
+}
+
+// Transition opens or closes an issue, the only states GitHub issues have.
+func (g *GitHub) Transition(ctx context.Context, id, state string) error {
+	number, err := issueNumber(id)
+	if err != nil {
+		return err
+	}
+	state = strings.ToLower(state)
+	if state != "open" && state != "closed" {
+		return fmt.Errorf("unknown GitHub issue state %q, expected open or closed", state)
+	}
+	return g.Client.SetIssueState(ctx, g.Owner, g.Repo, number, state)
+}
+
+func (g *GitHub) Comment(ctx context.Context, id, text string) error {
+	number, err := issueNumber(id)
+	if err != nil {
+		return err
+	}
+	return g.Client.CreateIssueComment(ctx, g.Owner, g.Repo, number, text)
+}
+
+// issueNumber parses an issue number, with or without a leading #.
+func issueNumber(id string) (int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(id, "#"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid GitHub issue number %q", id)
+	}
+	return number, nil
+}
@@ -0,0 +1,171 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultJQL selects the issues assigned to the current user that are in progress.
+const DefaultJQL = `assignee = currentUser() AND statusCategory = "In Progress" ORDER BY updated DESC`
+
+// Jira is a Jira Cloud or Jira Server/Data Center instance, accessed through its REST API v2.
+type Jira struct {
+	Client  *http.Client
+	BaseURL string
+	// Email is set for Jira Cloud, which authenticates with the email and an API token.
+	// Jira Server uses the token as a personal access token instead.
+	Email string
+	Token string
+	// JQL is the query ListMine runs
+	JQL string
+}
+
+func NewJira(baseURL, email, token string) *Jira {
+	return &Jira{
+		Client:  &http.Client{},
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Email:   email,
+		Token:   token,
+		JQL:     DefaultJQL,
+	}
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Status      struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+	} `json:"fields"`
+}
+
+func (j *Jira) issue(i jiraIssue) Issue {
+	return Issue{
+		ID:          i.Key,
+		Title:       i.Fields.Summary,
+		Description: i.Fields.Description,
+		URL:         j.BaseURL + "/browse/" + i.Key,
+		State:       i.Fields.Status.Name,
+		Type:        i.Fields.IssueType.Name,
+	}
+}
+
+const jiraFields = "summary,description,status,issuetype"
+
+func (j *Jira) ListMine(ctx context.Context) ([]Issue, error) {
+	query := url.Values{}
+	query.Set("jql", j.JQL)
+	query.Set("fields", jiraFields)
+	query.Set("maxResults", "100")
+
+	// Jira Cloud replaced the search endpoint, Jira Server only has the original one
+	path := "/rest/api/2/search"
+	if j.Email != "" {
+		path = "/rest/api/2/search/jql"
+	}
+
+	var result struct {
+		Issues []jiraIssue `json:"issues"`
+	}
+	if err := j.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, len(result.Issues))
+	for i, issue := range result.Issues {
+		issues[i] = j.issue(issue)
+	}
+	return issues, nil
+}
+
+func (j *Jira) Get(ctx context.Context, id string) (*Issue, error) {
+	var result jiraIssue
+	if err := j.do(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(id)+"?fields="+jiraFields, nil, &result); err != nil {
+		return nil, err
+	}
+	issue := j.issue(result)
+	return &issue, nil
+}
+
+// Transition applies the workflow transition whose name, or target status, matches state.
+func (j *Jira) Transition(ctx context.Context, id, state string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(id) + "/transitions"
+
+	var result struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := j.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return err
+	}
+
+	var available []string
+	for _, t := range result.Transitions {
+		if strings.EqualFold(t.Name, state) || strings.EqualFold(t.To.Name, state) {
+			body := map[string]any{"transition": map[string]string{"id": t.ID}}
+			return j.do(ctx, http.MethodPost, path, body, nil)
+		}
+		available = append(available, t.To.Name)
+	}
+	return fmt.Errorf("%s cannot be moved to %q, available states: %s", id, state, strings.Join(available, ", "))
+}
+
+func (j *Jira) Comment(ctx context.Context, id, text string) error {
+	body := map[string]string{"body": text}
+	return j.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(id)+"/comment", body, nil)
+}
+
+func (j *Jira) do(ctx context.Context, method, path string, body, out any) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return fmt.Errorf("failed to marshal payload: %v", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, j.BaseURL+path, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if j.Email != "" {
+		req.SetBasicAuth(j.Email, j.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.Token)
+	}
+
+	resp, err := j.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse("Jira", resp); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	return nil
+}
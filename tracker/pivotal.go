@@ -0,0 +1,129 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nlgtEA/lazyai/pivotal"
+)
+
+// Pivotal is one or more Pivotal Tracker projects.
+type Pivotal struct {
+	Client     *pivotal.Client
+	ProjectIDs []int
+	// Filter selects the stories ListMine returns
+	Filter pivotal.StoryFilter
+	// Iteration limits ListMine to the current iteration, the backlog or the icebox when set
+	Iteration string
+}
+
+func NewPivotal(client *pivotal.Client, projectIDs []int, owner string) *Pivotal {
+	return &Pivotal{
+		Client:     client,
+		ProjectIDs: projectIDs,
+		Filter:     pivotal.StoryFilter{Owner: owner, States: []string{pivotal.Started}},
+	}
+}
+
+// StoryIssue converts a Pivotal Tracker story to an Issue.
+func StoryIssue(s pivotal.Story) Issue {
+	return Issue{
+		ID:          strconv.Itoa(s.ID),
+		Title:       s.Name,
+		Description: s.Description,
+		URL:         s.URL,
+		State:       s.CurrentState,
+		Type:        s.StoryType,
+		Labels:      s.LabelNames(),
+		Estimate:    s.Estimate,
+	}
+}
+
+// ListMine returns the stories matching Filter in every project, named after their project when there are several.
+func (p *Pivotal) ListMine(ctx context.Context) ([]Issue, error) {
+	filter := p.Filter
+	if p.Iteration == pivotal.Icebox {
+		filter.States = []string{pivotal.Unscheduled}
+	}
+
+	var issues []Issue
+	for _, projectID := range p.ProjectIDs {
+		stories, err := p.Client.Stories(ctx, projectID, filter.String())
+		if err != nil {
+			return nil, err
+		}
+
+		if p.Iteration != "" && p.Iteration != pivotal.Icebox {
+			ids, err := p.Client.IterationStoryIDs(ctx, projectID, p.Iteration)
+			if err != nil {
+				return nil, err
+			}
+			stories = slices.DeleteFunc(stories, func(s pivotal.Story) bool { return !ids[s.ID] })
+		}
+
+		projectName := ""
+		if len(p.ProjectIDs) > 1 && len(stories) > 0 {
+			project, err := p.Client.Project(ctx, projectID)
+			if err != nil {
+				return nil, err
+			}
+			projectName = project.Name
+		}
+
+		for _, story := range stories {
+			issue := StoryIssue(story)
+			issue.Project = projectName
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// Get returns a story of any project the user can access.
+func (p *Pivotal) Get(ctx context.Context, id string) (*Issue, error) {
+	story, err := p.story(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	issue := StoryIssue(*story)
+	return &issue, nil
+}
+
+func (p *Pivotal) story(ctx context.Context, id string) (*pivotal.Story, error) {
+	storyID, err := StoryID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Client.StoryByID(ctx, storyID)
+}
+
+func (p *Pivotal) Transition(ctx context.Context, id, state string) error {
+	story, err := p.story(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = p.Client.UpdateStory(ctx, story.ProjectID, story.ID, pivotal.StoryUpdate{CurrentState: &state})
+	return err
+}
+
+func (p *Pivotal) Comment(ctx context.Context, id, text string) error {
+	story, err := p.story(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = p.Client.CreateComment(ctx, story.ProjectID, story.ID, pivotal.NewComment{Text: text})
+	return err
+}
+
+// StoryID parses a story ID, with or without a leading #.
+func StoryID(id string) (int, error) {
+	storyID, err := strconv.Atoi(strings.TrimPrefix(id, "#"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid story ID %q", id)
+	}
+	return storyID, nil
+}
@@ -0,0 +1,54 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Issue is a story, ticket or issue of a tracker.
+type Issue struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	State       string `json:"state"`
+	Type        string `json:"type"`
+	// Labels and Estimate are only known for some trackers
+	Labels   []string `json:"labels,omitempty"`
+	Estimate *float64 `json:"estimate,omitempty"`
+	// Project names the project of the issue when issues come from several projects
+	Project string `json:"project,omitempty"`
+}
+
+// Tracker is a project management tool lazyai can pick work from.
+type Tracker interface {
+	// ListMine returns the issues the current user is actively working on.
+	ListMine(ctx context.Context) ([]Issue, error)
+	// Get returns a single issue.
+	Get(ctx context.Context, id string) (*Issue, error)
+	// Transition moves an issue to another state.
+	Transition(ctx context.Context, id, state string) error
+	// Comment adds a comment to an issue.
+	Comment(ctx context.Context, id, text string) error
+}
+
+// APIError is returned when a tracker responds with an unexpected status code.
+type APIError struct {
+	Tracker    string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s responded with %d: %s", e.Tracker, e.StatusCode, e.Body)
+}
+
+func checkResponse(tracker string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return &APIError{Tracker: tracker, StatusCode: resp.StatusCode, Body: string(body)}
+}